@@ -0,0 +1,465 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// ArrayValue is a slice that is used as a PostgreSQL array.
+//
+// Unlike a plain slice, which is encoded as a `(a,b,c)` tuple for IN,
+// it is encoded as an `ARRAY[a,b,c]` literal. It can also be used as
+// a scan destination if it wraps a pointer to a slice.
+type ArrayValue struct {
+	v interface{}
+}
+
+// Array creates an ArrayValue from a slice, or a pointer to a slice.
+func Array(value interface{}) *ArrayValue {
+	return &ArrayValue{v: value}
+}
+
+// Build writes an `ARRAY[...]` literal.
+// It returns ErrNotSupported for dialects without array types.
+func (a *ArrayValue) Build(d Dialect, buf Buffer) error {
+	if arrayStyle(d) != dialect.ArrayConstructor {
+		return ErrNotSupported
+	}
+	v := reflect.Indirect(reflect.ValueOf(a.v))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return ErrNotSupported
+	}
+	if v.Len() == 0 {
+		// ARRAY[] requires an explicit type; '{}' takes the type from its context.
+		buf.WriteString("'{}'")
+		return nil
+	}
+	buf.WriteString("ARRAY[")
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Interface && !elem.IsNil() {
+			// the dynamic value of an element of []interface{}
+			elem = elem.Elem()
+		}
+		if isArrayType(elem.Type()) {
+			// multi-dimensional array
+			err := Array(elem.Interface()).Build(d, buf)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		buf.WriteString(placeholder)
+		buf.WriteValue(elem.Interface())
+	}
+	buf.WriteString("]")
+	return nil
+}
+
+// Value implements driver.Valuer with PostgreSQL array text format.
+func (a *ArrayValue) Value() (driver.Value, error) {
+	v := reflect.Indirect(reflect.ValueOf(a.v))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, ErrNotSupported
+	}
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return nil, nil
+	}
+	var buf strings.Builder
+	err := encodeArrayText(&buf, v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+// Scan implements sql.Scanner with PostgreSQL array text format.
+func (a *ArrayValue) Scan(src interface{}) error {
+	v := reflect.ValueOf(a.v)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return ErrInvalidPointer
+	}
+	v = v.Elem()
+
+	var text []byte
+	switch src := src.(type) {
+	case nil:
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	case []byte:
+		text = src
+	case string:
+		text = []byte(src)
+	default:
+		return fmt.Errorf("dbr: cannot scan %T into array", src)
+	}
+
+	p := arrayParser{s: text}
+	elems, err := p.parse()
+	if err != nil {
+		return err
+	}
+	return assignArray(v, elems)
+}
+
+var (
+	_ Builder       = (*ArrayValue)(nil)
+	_ driver.Valuer = (*ArrayValue)(nil)
+	_ sql.Scanner   = (*ArrayValue)(nil)
+)
+
+// isArrayType reports whether t is a slice that should be scanned
+// from, or encoded as, an array column.
+func isArrayType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice &&
+		t.Elem().Kind() != reflect.Uint8 &&
+		!reflect.PtrTo(t).Implements(typeScanner) &&
+		!t.Implements(typeValuer)
+}
+
+// Any is `= ANY(...)`.
+// When value is a slice, it is used as an array.
+func Any(column string, value interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(d.QuoteIdent(column))
+		buf.WriteString(" = ANY(")
+		buf.WriteString(placeholder)
+		buf.WriteString(")")
+		buf.WriteValue(arrayArg(value))
+		return nil
+	})
+}
+
+// ArrayContains is `@>`; column contains all elements of value.
+func ArrayContains(column string, value interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		return buildCmp(d, buf, "@>", column, arrayArg(value))
+	})
+}
+
+// ArrayOverlap is `&&`; column and value have elements in common.
+func ArrayOverlap(column string, value interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		return buildCmp(d, buf, "&&", column, arrayArg(value))
+	})
+}
+
+func arrayArg(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if isArrayType(reflect.TypeOf(value)) {
+		return Array(value)
+	}
+	return value
+}
+
+func encodeArrayText(buf *strings.Builder, v reflect.Value) error {
+	buf.WriteString("{")
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		err := encodeArrayElem(buf, v.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+func encodeArrayElem(buf *strings.Builder, value interface{}) error {
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		value, err = valuer.Value()
+		if err != nil {
+			return err
+		}
+	}
+	if value == nil {
+		buf.WriteString("NULL")
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		writeArrayQuoted(buf, v.String())
+		return nil
+	case reflect.Bool:
+		if v.Bool() {
+			buf.WriteString("t")
+		} else {
+			buf.WriteString("f")
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		buf.WriteString(strconv.FormatFloat(v.Float(), 'f', -1, 64))
+		return nil
+	case reflect.Struct:
+		if v.Type() == typeTime {
+			writeArrayQuoted(buf, v.Interface().(time.Time).Format(time.RFC3339Nano))
+			return nil
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			writeArrayQuoted(buf, `\x`+hex.EncodeToString(v.Bytes()))
+			return nil
+		}
+		return encodeArrayText(buf, v)
+	case reflect.Ptr:
+		if v.IsNil() {
+			buf.WriteString("NULL")
+			return nil
+		}
+		return encodeArrayElem(buf, v.Elem().Interface())
+	}
+	return ErrNotSupported
+}
+
+func writeArrayQuoted(buf *strings.Builder, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(s[i])
+	}
+	buf.WriteByte('"')
+}
+
+// arrayParser parses PostgreSQL array text format like `{1,"a b",NULL,{2,3}}`.
+//
+// Each parsed element is either *string, which is nil for NULL,
+// or []interface{} for a nested array.
+type arrayParser struct {
+	s   []byte
+	pos int
+}
+
+func (p *arrayParser) parse() ([]interface{}, error) {
+	// skip optional dimension decoration like `[1:3]=`
+	if len(p.s) > 0 && p.s[0] == '[' {
+		i := bytes.IndexByte(p.s, '=')
+		if i == -1 {
+			return nil, ErrInvalidArray
+		}
+		p.pos = i + 1
+	}
+	elems, err := p.parseArray()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, ErrInvalidArray
+	}
+	return elems, nil
+}
+
+func (p *arrayParser) parseArray() ([]interface{}, error) {
+	if p.pos >= len(p.s) || p.s[p.pos] != '{' {
+		return nil, ErrInvalidArray
+	}
+	p.pos++
+	elems := []interface{}{}
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return elems, nil
+	}
+	for {
+		if p.pos >= len(p.s) {
+			return nil, ErrInvalidArray
+		}
+		switch p.s[p.pos] {
+		case '{':
+			elem, err := p.parseArray()
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		case '"':
+			elem, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, &elem)
+		default:
+			start := p.pos
+			for p.pos < len(p.s) && p.s[p.pos] != ',' && p.s[p.pos] != '}' {
+				p.pos++
+			}
+			elem := string(p.s[start:p.pos])
+			if strings.EqualFold(elem, "NULL") {
+				elems = append(elems, (*string)(nil))
+			} else {
+				elems = append(elems, &elem)
+			}
+		}
+		if p.pos >= len(p.s) {
+			return nil, ErrInvalidArray
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return elems, nil
+		default:
+			return nil, ErrInvalidArray
+		}
+	}
+}
+
+func (p *arrayParser) parseQuoted() (string, error) {
+	var buf strings.Builder
+	p.pos++ // opening quote
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", ErrInvalidArray
+			}
+			buf.WriteByte(p.s[p.pos])
+			p.pos++
+		case '"':
+			return buf.String(), nil
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "", ErrInvalidArray
+}
+
+func assignArray(v reflect.Value, elems []interface{}) error {
+	s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		err := assignArrayElem(s.Index(i), elem)
+		if err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func assignArrayElem(v reflect.Value, elem interface{}) error {
+	if nested, ok := elem.([]interface{}); ok {
+		if v.Kind() != reflect.Slice {
+			return ErrNotSupported
+		}
+		return assignArray(v, nested)
+	}
+	text := elem.(*string)
+
+	if v.Addr().Type().Implements(typeScanner) {
+		var src interface{}
+		if text != nil {
+			src = *text
+		}
+		return v.Addr().Interface().(sql.Scanner).Scan(src)
+	}
+	if v.Kind() == reflect.Ptr {
+		if text == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return assignArrayElem(v.Elem(), elem)
+	}
+	if text == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	s := *text
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.Struct:
+		if v.Type() == typeTime {
+			t, err := parseArrayTime(s)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := hex.DecodeString(strings.TrimPrefix(s, `\x`))
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+	}
+	return ErrNotSupported
+}
+
+var arrayTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+func parseArrayTime(s string) (time.Time, error) {
+	for _, layout := range arrayTimeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidTimestring
+}
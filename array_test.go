@@ -0,0 +1,151 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayInterpolate(t *testing.T) {
+	for _, test := range []struct {
+		value interface{}
+		want  string
+	}{
+		{
+			value: Array([]int64{1, 2, 3}),
+			want:  "ARRAY[1,2,3]",
+		},
+		{
+			value: Array([]string{"a", "b'c"}),
+			want:  "ARRAY['a','b''c']",
+		},
+		{
+			value: Array([][]int{{1, 2}, {3, 4}}),
+			want:  "ARRAY[ARRAY[1,2],ARRAY[3,4]]",
+		},
+		{
+			value: Array([]interface{}{[]int{1, 2}, []string{"a"}, nil}),
+			want:  "ARRAY[ARRAY[1,2],ARRAY['a'],NULL]",
+		},
+		{
+			value: Array([]string{}),
+			want:  "'{}'",
+		},
+		{
+			value: Any("id", []int64{1, 2}),
+			want:  `"id" = ANY(ARRAY[1,2])`,
+		},
+		{
+			value: ArrayContains("tags", []string{"go"}),
+			want:  `"tags" @> ARRAY['go']`,
+		},
+		{
+			value: ArrayOverlap("tags", Array([]string{"go", "sql"})),
+			want:  `"tags" && ARRAY['go','sql']`,
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.value}, dialect.PostgreSQL)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+	}
+
+	_, err := InterpolateForDialect("?", []interface{}{Array([]int64{1})}, dialect.MySQL)
+	require.Equal(t, ErrNotSupported, err)
+}
+
+func TestArrayValue(t *testing.T) {
+	v, err := Array([]interface{}{1, "a \"b\"", nil, true, []byte{0xab}}).Value()
+	require.NoError(t, err)
+	require.Equal(t, `{1,"a \"b\"",NULL,t,"\\xab"}`, v)
+
+	v, err = Array([]int64(nil)).Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
+
+func TestArrayScan(t *testing.T) {
+	var ints []int64
+	require.NoError(t, Array(&ints).Scan([]byte("{1,2,3}")))
+	require.Equal(t, []int64{1, 2, 3}, ints)
+
+	var strs []string
+	require.NoError(t, Array(&strs).Scan(`{a,"b,c","d \"e\"",NULL}`))
+	require.Equal(t, []string{"a", "b,c", `d "e"`, ""}, strs)
+
+	var ptrs []*string
+	require.NoError(t, Array(&ptrs).Scan(`{a,NULL}`))
+	require.Equal(t, "a", *ptrs[0])
+	require.Nil(t, ptrs[1])
+
+	var nulls []NullInt64
+	require.NoError(t, Array(&nulls).Scan(`{1,NULL}`))
+	require.Equal(t, []NullInt64{NewNullInt64(1), {}}, nulls)
+
+	var matrix [][]int
+	require.NoError(t, Array(&matrix).Scan(`[0:1][0:1]={{1,2},{3,4}}`))
+	require.Equal(t, [][]int{{1, 2}, {3, 4}}, matrix)
+
+	var bytea [][]byte
+	require.NoError(t, Array(&bytea).Scan(`{"\\x0102"}`))
+	require.Equal(t, [][]byte{{1, 2}}, bytea)
+
+	require.NoError(t, Array(&ints).Scan(nil))
+	require.Nil(t, ints)
+
+	require.Equal(t, ErrInvalidArray, Array(&ints).Scan(`{1,2`))
+	require.Equal(t, ErrInvalidPointer, Array(ints).Scan(`{1}`))
+}
+
+func TestArrayLoad(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.PostgreSQL,
+	}
+	sess := conn.NewSession(nil)
+
+	type post struct {
+		ID   int64
+		Tags []string
+		Refs []int64
+	}
+
+	mock.ExpectQuery("SELECT id, tags, refs FROM posts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags", "refs"}).
+			AddRow(1, []byte("{go,sql}"), []byte("{10,20}")).
+			AddRow(2, []byte("{}"), nil))
+	var posts []post
+	count, err := sess.Select("id", "tags", "refs").From("posts").Load(&posts)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []post{
+		{ID: 1, Tags: []string{"go", "sql"}, Refs: []int64{10, 20}},
+		{ID: 2, Tags: []string{}},
+	}, posts)
+
+	mock.ExpectQuery("SELECT refs FROM posts").
+		WillReturnRows(sqlmock.NewRows([]string{"refs"}).AddRow([]byte("{1,2}")).AddRow([]byte("{3}")))
+	var refs [][]int64
+	count, err = sess.Select("refs").From("posts").Load(&refs)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, [][]int64{{1, 2}, {3}}, refs)
+
+	mock.ExpectQuery("SELECT id, tags FROM posts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags"}).AddRow(1, "{a}"))
+	iter, err := sess.Select("id", "tags").From("posts").Iterate()
+	require.NoError(t, err)
+	var p post
+	for iter.Next() {
+		require.NoError(t, iter.Scan(&p))
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, post{ID: 1, Tags: []string{"a"}}, p)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,444 @@
+package dbr
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// bulkUpdateAlias is the alias of the derived table that holds new values.
+const bulkUpdateAlias = "dbr_bulk"
+
+// BulkUpdateStmt updates many rows, each with its own values, in one statement.
+//
+// Rows are matched by KeyColumn. The statement is built in the syntax of each dialect:
+//
+//	PostgreSQL:      UPDATE ... SET ... FROM (VALUES ...) AS ... WHERE ...
+//	MySQL:           UPDATE ... JOIN (SELECT ... UNION ALL SELECT ...) AS ... ON ... SET ...
+//	MSSQL:           UPDATE ... SET ... FROM ... JOIN (VALUES ...) AS ... ON ...
+//	SQLite3, Oracle: UPDATE ... SET col = CASE WHEN ... THEN ... END WHERE ...
+//
+// Other dialects return ErrNotSupported, unless they implement BulkUpdateStyler.
+type BulkUpdateStmt struct {
+	Runner
+	EventReceiver
+	Dialect
+
+	Table     string
+	KeyColumn []string
+	Column    []string
+	// Value holds rows of key values followed by column values.
+	Value      [][]interface{}
+	ColumnType map[string]string
+	WhereCond  []Builder
+	BatchLimit *BatchLimit
+
+	comments Comments
+}
+
+// BulkUpdate creates a BulkUpdateStmt.
+func BulkUpdate(table string) *BulkUpdateStmt {
+	return &BulkUpdateStmt{
+		Table: table,
+	}
+}
+
+// BulkUpdate creates a BulkUpdateStmt.
+func (sess *Session) BulkUpdate(table string) *BulkUpdateStmt {
+	b := BulkUpdate(table)
+	b.Runner = sess
+	b.EventReceiver = sess.EventReceiver
+	b.Dialect = sess.Dialect
+	return b
+}
+
+// BulkUpdate creates a BulkUpdateStmt.
+func (tx *Tx) BulkUpdate(table string) *BulkUpdateStmt {
+	b := BulkUpdate(table)
+	b.Runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// Keys specifies the columns that identify each row.
+func (b *BulkUpdateStmt) Keys(column ...string) *BulkUpdateStmt {
+	b.KeyColumn = column
+	return b
+}
+
+// Columns specifies the columns to update.
+func (b *BulkUpdateStmt) Columns(column ...string) *BulkUpdateStmt {
+	b.Column = column
+	return b
+}
+
+// Type casts new values of column to sqlType, like `timestamptz`.
+// This is needed when the database cannot infer the type of literals,
+// like PostgreSQL does with VALUES.
+func (b *BulkUpdateStmt) Type(column, sqlType string) *BulkUpdateStmt {
+	if b.ColumnType == nil {
+		b.ColumnType = make(map[string]string)
+	}
+	b.ColumnType[column] = sqlType
+	return b
+}
+
+// Values adds a row to update.
+// The key values in the order of Keys come first, then the values in the order of Columns.
+func (b *BulkUpdateStmt) Values(value ...interface{}) *BulkUpdateStmt {
+	b.Value = append(b.Value, value)
+	return b
+}
+
+// Record adds a row to update from a struct.
+func (b *BulkUpdateStmt) Record(structValue interface{}) *BulkUpdateStmt {
+	v := reflect.Indirect(reflect.ValueOf(structValue))
+
+	if v.Kind() == reflect.Struct {
+		column := append(append([]string{}, b.KeyColumn...), b.Column...)
+		value := make([]interface{}, len(column))
+		s := newTagStore()
+		s.findValueByName(v, column, value, false)
+		for i, v := range value {
+			if v != nil {
+				value[i] = v.(reflect.Value).Interface()
+			}
+		}
+		b.Values(value...)
+	}
+	return b
+}
+
+// Records adds a row to update for each struct in slice.
+func (b *BulkUpdateStmt) Records(slice interface{}) *BulkUpdateStmt {
+	v := reflect.Indirect(reflect.ValueOf(slice))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return b
+	}
+	for i := 0; i < v.Len(); i++ {
+		b.Record(v.Index(i).Interface())
+	}
+	return b
+}
+
+// Where adds a where condition, in addition to matching keys.
+// query can be Builder or string. value is used only if query type is string.
+func (b *BulkUpdateStmt) Where(query interface{}, value ...interface{}) *BulkUpdateStmt {
+	switch query := query.(type) {
+	case string:
+		b.WhereCond = append(b.WhereCond, Expr(query, value...))
+	case Builder:
+		b.WhereCond = append(b.WhereCond, query)
+	}
+	return b
+}
+
+// Batch overrides the limits that Exec uses to split rows.
+func (b *BulkUpdateStmt) Batch(limit BatchLimit) *BulkUpdateStmt {
+	b.BatchLimit = &limit
+	return b
+}
+
+func (b *BulkUpdateStmt) Comment(comment string) *BulkUpdateStmt {
+	b.comments = b.comments.Append(comment)
+	return b
+}
+
+// ToSQL returns the query with placeholders of dialect d, and its arguments.
+func (b *BulkUpdateStmt) ToSQL(d Dialect) (string, []interface{}, error) {
+	return ToSQL(b, d)
+}
+
+func (b *BulkUpdateStmt) Build(d Dialect, buf Buffer) error {
+	if b.Table == "" {
+		return ErrTableNotSpecified
+	}
+
+	if len(b.KeyColumn) == 0 || len(b.Column) == 0 {
+		return ErrColumnNotSpecified
+	}
+
+	if len(b.Value) == 0 {
+		return ErrValueNotSpecified
+	}
+
+	for _, tuple := range b.Value {
+		if len(tuple) != len(b.KeyColumn)+len(b.Column) {
+			return ErrPlaceholderCount
+		}
+	}
+
+	err := b.comments.Build(d, buf)
+	if err != nil {
+		return err
+	}
+
+	switch bulkUpdateStyle(d) {
+	case dialect.UpdateFromValues:
+		return b.buildValuesFrom(d, buf)
+	case dialect.UpdateJoinUnion:
+		return b.buildJoin(d, buf)
+	case dialect.UpdateJoinValues:
+		return b.buildJoinValues(d, buf)
+	case dialect.UpdateCase:
+		return b.buildCase(d, buf)
+	}
+	return ErrNotSupported
+}
+
+// UPDATE "t" SET "a" = "dbr_bulk"."a" FROM (VALUES (1,'x')) AS "dbr_bulk" ("id","a") WHERE "t"."id" = "dbr_bulk"."id"
+func (b *BulkUpdateStmt) buildValuesFrom(d Dialect, buf Buffer) error {
+	buf.WriteString("UPDATE ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	b.buildSet(d, buf, false)
+	buf.WriteString(" FROM ")
+	b.buildValues(d, buf)
+	buf.WriteString(" WHERE ")
+	b.buildKeyMatch(d, buf)
+	return b.buildWhere(d, buf, " AND ")
+}
+
+// UPDATE `t` JOIN (SELECT 1 AS `id`, 'x' AS `a` UNION ALL ...) AS `dbr_bulk` ON `t`.`id` = `dbr_bulk`.`id` SET `t`.`a` = `dbr_bulk`.`a`
+func (b *BulkUpdateStmt) buildJoin(d Dialect, buf Buffer) error {
+	buf.WriteString("UPDATE ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(" JOIN (")
+	column := b.allColumns()
+	for i, tuple := range b.Value {
+		if i > 0 {
+			buf.WriteString(" UNION ALL ")
+		}
+		buf.WriteString("SELECT ")
+		for j, value := range tuple {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(value)
+			if i == 0 {
+				buf.WriteString(" AS ")
+				buf.WriteString(d.QuoteIdent(column[j]))
+			}
+		}
+	}
+	buf.WriteString(") AS ")
+	buf.WriteString(d.QuoteIdent(bulkUpdateAlias))
+	buf.WriteString(" ON ")
+	b.buildKeyMatch(d, buf)
+	b.buildSet(d, buf, true)
+	return b.buildWhere(d, buf, " WHERE ")
+}
+
+// UPDATE "t" SET "a" = "dbr_bulk"."a" FROM "t" JOIN (VALUES (1,'x')) AS "dbr_bulk" ("id","a") ON "t"."id" = "dbr_bulk"."id"
+func (b *BulkUpdateStmt) buildJoinValues(d Dialect, buf Buffer) error {
+	buf.WriteString("UPDATE ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	b.buildSet(d, buf, false)
+	buf.WriteString(" FROM ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(" JOIN ")
+	b.buildValues(d, buf)
+	buf.WriteString(" ON ")
+	b.buildKeyMatch(d, buf)
+	return b.buildWhere(d, buf, " WHERE ")
+}
+
+// UPDATE "t" SET "a" = CASE WHEN "id" = 1 THEN 'x' ELSE "a" END WHERE "id" IN (1)
+func (b *BulkUpdateStmt) buildCase(d Dialect, buf Buffer) error {
+	buf.WriteString("UPDATE ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(" SET ")
+	keys := len(b.KeyColumn)
+	for i, col := range b.Column {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+		buf.WriteString(" = CASE")
+		for _, tuple := range b.Value {
+			buf.WriteString(" WHEN ")
+			b.buildKeyEq(d, buf, tuple)
+			buf.WriteString(" THEN ")
+			b.buildCast(d, buf, col, placeholder)
+			buf.WriteValue(tuple[keys+i])
+		}
+		buf.WriteString(" ELSE ")
+		buf.WriteString(d.QuoteIdent(col))
+		buf.WriteString(" END")
+	}
+	buf.WriteString(" WHERE ")
+	if keys == 1 {
+		buf.WriteString(d.QuoteIdent(b.KeyColumn[0]))
+		buf.WriteString(" IN (")
+		for i, tuple := range b.Value {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(tuple[0])
+		}
+		buf.WriteString(")")
+	} else {
+		buf.WriteString("(")
+		for i, tuple := range b.Value {
+			if i > 0 {
+				buf.WriteString(" OR ")
+			}
+			b.buildKeyEq(d, buf, tuple)
+		}
+		buf.WriteString(")")
+	}
+	return b.buildWhere(d, buf, " AND ")
+}
+
+func (b *BulkUpdateStmt) allColumns() []string {
+	return append(append([]string{}, b.KeyColumn...), b.Column...)
+}
+
+func (b *BulkUpdateStmt) buildSet(d Dialect, buf Buffer, qualified bool) {
+	buf.WriteString(" SET ")
+	for i, col := range b.Column {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if qualified {
+			buf.WriteString(d.QuoteIdent(b.Table + "." + col))
+		} else {
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		buf.WriteString(" = ")
+		b.buildCast(d, buf, col, d.QuoteIdent(bulkUpdateAlias+"."+col))
+	}
+}
+
+func (b *BulkUpdateStmt) buildCast(d Dialect, buf Buffer, column, expr string) {
+	sqlType, ok := b.ColumnType[column]
+	if !ok {
+		buf.WriteString(expr)
+		return
+	}
+	buf.WriteString("CAST(")
+	buf.WriteString(expr)
+	buf.WriteString(" AS ")
+	buf.WriteString(sqlType)
+	buf.WriteString(")")
+}
+
+// (VALUES (?,?),(?,?)) AS "dbr_bulk" ("id","a")
+func (b *BulkUpdateStmt) buildValues(d Dialect, buf Buffer) {
+	buf.WriteString("(VALUES ")
+	for i, tuple := range b.Value {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("(")
+		for j, value := range tuple {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(value)
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(") AS ")
+	buf.WriteString(d.QuoteIdent(bulkUpdateAlias))
+	buf.WriteString(" (")
+	for i, col := range b.allColumns() {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	buf.WriteString(")")
+}
+
+func (b *BulkUpdateStmt) buildKeyMatch(d Dialect, buf Buffer) {
+	for i, key := range b.KeyColumn {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		buf.WriteString(d.QuoteIdent(b.Table + "." + key))
+		buf.WriteString(" = ")
+		b.buildCast(d, buf, key, d.QuoteIdent(bulkUpdateAlias+"."+key))
+	}
+}
+
+func (b *BulkUpdateStmt) buildKeyEq(d Dialect, buf Buffer, tuple []interface{}) {
+	if len(b.KeyColumn) > 1 {
+		buf.WriteString("(")
+	}
+	for i, key := range b.KeyColumn {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		buf.WriteString(d.QuoteIdent(key))
+		buf.WriteString(" = ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(tuple[i])
+	}
+	if len(b.KeyColumn) > 1 {
+		buf.WriteString(")")
+	}
+}
+
+func (b *BulkUpdateStmt) buildWhere(d Dialect, buf Buffer, pred string) error {
+	if len(b.WhereCond) == 0 {
+		return nil
+	}
+	buf.WriteString(pred)
+	return And(b.WhereCond...).Build(d, buf)
+}
+
+func (b *BulkUpdateStmt) Exec() (BatchResult, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext updates rows in as many statements as needed
+// to stay within the limits of the dialect, like InsertStmt.ExecBatch.
+func (b *BulkUpdateStmt) ExecContext(ctx context.Context) (BatchResult, error) {
+	var res BatchResult
+	if len(b.Value) == 0 {
+		return res, nil
+	}
+
+	limit := defaultBatchLimit(b.Dialect)
+	if b.BatchLimit != nil {
+		limit = *b.BatchLimit
+	}
+	repeat := 1
+	switch bulkUpdateStyle(b.Dialect) {
+	case dialect.NoBulkUpdate:
+		return res, ErrNotSupported
+	case dialect.UpdateCase:
+		// values appear once per column in CASE, and once more in WHERE
+		repeat = len(b.Column) + 1
+		// keys are in IN of WHERE
+		if max := maxInList(b.Dialect); max > 0 && (limit.MaxRows == 0 || limit.MaxRows > max) {
+			limit.MaxRows = max
+		}
+	}
+	bind := usePreparedStmt(b.Runner) || useBindParams(b.Runner)
+	batches, err := splitBatch(b.Dialect, limit, 0, b.Value, repeat, bind)
+	if err != nil {
+		return res, err
+	}
+
+	for _, value := range batches {
+		stmt := *b
+		stmt.Value = value
+		result, err := exec(ctx, stmt.Runner, stmt.EventReceiver, &stmt, stmt.Dialect)
+		if err != nil {
+			return res, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return res, err
+		}
+		res.RowsAffected += n
+		res.Batches++
+	}
+	return res, nil
+}
@@ -0,0 +1,112 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkUpdateStmt(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		stmt  *BulkUpdateStmt
+		query string
+	}{
+		{
+			d: dialect.PostgreSQL,
+			stmt: BulkUpdate("people").Keys("id").Columns("name", "born").Type("born", "date").
+				Values(1, "a", "2000-01-01").Values(2, "b", "2001-01-01"),
+			query: `UPDATE "people" SET "name" = "dbr_bulk"."name", "born" = CAST("dbr_bulk"."born" AS date) ` +
+				`FROM (VALUES (1,'a','2000-01-01'),(2,'b','2001-01-01')) AS "dbr_bulk" ("id","name","born") ` +
+				`WHERE "people"."id" = "dbr_bulk"."id"`,
+		},
+		{
+			// Type allocates ColumnType of a literal statement
+			d: dialect.PostgreSQL,
+			stmt: (&BulkUpdateStmt{Table: "people"}).Keys("id").Columns("born").Type("born", "date").
+				Values(1, "2000-01-01"),
+			query: `UPDATE "people" SET "born" = CAST("dbr_bulk"."born" AS date) ` +
+				`FROM (VALUES (1,'2000-01-01')) AS "dbr_bulk" ("id","born") ` +
+				`WHERE "people"."id" = "dbr_bulk"."id"`,
+		},
+		{
+			d:    dialect.MySQL,
+			stmt: BulkUpdate("people").Keys("id").Columns("name").Values(1, "a").Values(2, "b").Where(Eq("active", true)),
+			query: "UPDATE `people` JOIN (SELECT 1 AS `id`, 'a' AS `name` UNION ALL SELECT 2, 'b') AS `dbr_bulk` " +
+				"ON `people`.`id` = `dbr_bulk`.`id` SET `people`.`name` = `dbr_bulk`.`name` WHERE (`active` = 1)",
+		},
+		{
+			d:    dialect.MSSQL,
+			stmt: BulkUpdate("people").Keys("id").Columns("name").Values(1, "a").Values(2, "b"),
+			query: `UPDATE "people" SET "name" = "dbr_bulk"."name" FROM "people" ` +
+				`JOIN (VALUES (1,'a'),(2,'b')) AS "dbr_bulk" ("id","name") ON "people"."id" = "dbr_bulk"."id"`,
+		},
+		{
+			d:    dialect.SQLite3,
+			stmt: BulkUpdate("people").Keys("id").Columns("name", "email").Values(1, "a", "a@x").Values(2, "b", "b@x"),
+			query: `UPDATE "people" SET "name" = CASE WHEN "id" = 1 THEN 'a' WHEN "id" = 2 THEN 'b' ELSE "name" END, ` +
+				`"email" = CASE WHEN "id" = 1 THEN 'a@x' WHEN "id" = 2 THEN 'b@x' ELSE "email" END WHERE "id" IN (1,2)`,
+		},
+		{
+			d:    dialect.SQLite3,
+			stmt: BulkUpdate("stock").Keys("shop", "item").Columns("qty").Values(1, 2, 10).Values(1, 3, 20),
+			query: `UPDATE "stock" SET "qty" = CASE WHEN ("shop" = 1 AND "item" = 2) THEN 10 WHEN ("shop" = 1 AND "item" = 3) THEN 20 ELSE "qty" END ` +
+				`WHERE (("shop" = 1 AND "item" = 2) OR ("shop" = 1 AND "item" = 3))`,
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.stmt}, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.query, s)
+	}
+
+	err := BulkUpdate("people").Keys("id").Columns("name").Build(dialect.MySQL, NewBuffer())
+	require.Equal(t, ErrValueNotSpecified, err)
+
+	err = BulkUpdate("people").Keys("id").Columns("name").Values(1).Build(dialect.MySQL, NewBuffer())
+	require.Equal(t, ErrPlaceholderCount, err)
+}
+
+func TestBulkUpdateExec(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL, nil)
+
+	mock.ExpectExec(`UPDATE "dbr_people" SET "name" = "dbr_bulk"."name" FROM (VALUES (1,'a'),(2,'b')) AS "dbr_bulk" ("id","name") WHERE "dbr_people"."id" = "dbr_bulk"."id"`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE "dbr_people" SET "name" = "dbr_bulk"."name" FROM (VALUES (3,'c')) AS "dbr_bulk" ("id","name") WHERE "dbr_people"."id" = "dbr_bulk"."id"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := sess.BulkUpdate("dbr_people").
+		Keys("id").
+		Columns("name").
+		Records([]dbrPerson{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}, {Id: 3, Name: "c"}}).
+		Batch(BatchLimit{MaxRows: 2}).
+		Exec()
+	require.NoError(t, err)
+	require.Equal(t, BatchResult{RowsAffected: 3, Batches: 2}, res)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkUpdateExecMaxInList(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.Oracle, nil)
+
+	// oracle allows 1000 keys in IN
+	stmt := sess.BulkUpdate("dbr_people").Keys("id").Columns("name")
+	for i := 1; i <= 1001; i++ {
+		stmt.Values(i, "a")
+	}
+	for _, value := range [][][]interface{}{stmt.Value[:1000], stmt.Value[1000:]} {
+		batch := *stmt
+		batch.Value = value
+		query, err := InterpolateForDialect("?", []interface{}{&batch}, dialect.Oracle)
+		require.NoError(t, err)
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, int64(len(value))))
+	}
+	res, err := stmt.Exec()
+	require.NoError(t, err)
+	require.Equal(t, BatchResult{RowsAffected: 1001, Batches: 2}, res)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	_, _, err = BulkUpdate("events").Keys("id").Columns("name").Values(1, "a").ToSQL(dialect.ClickHouse)
+	require.Equal(t, ErrNotSupported, err)
+}
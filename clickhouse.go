@@ -0,0 +1,113 @@
+package dbr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// Final reads fully merged data from ReplacingMergeTree-like tables
+// with `FROM ... FINAL` in clickhouse.
+func (b *SelectStmt) Final() *SelectStmt {
+	b.IsFinal = true
+	return b
+}
+
+// Sample reads a fraction of data with `SAMPLE` in clickhouse.
+// ratio between 0 and 1 is a fraction, and a larger ratio is a number of rows.
+func (b *SelectStmt) Sample(ratio float64) *SelectStmt {
+	b.SampleRatio = ratio
+	return b
+}
+
+// Prewhere adds a prewhere condition, which clickhouse evaluates
+// before reading the other columns.
+// query can be Builder or string. value is used only if query type is string.
+func (b *SelectStmt) Prewhere(query interface{}, value ...interface{}) *SelectStmt {
+	switch query := query.(type) {
+	case string:
+		b.PrewhereCond = append(b.PrewhereCond, Expr(query, value...))
+	case Builder:
+		b.PrewhereCond = append(b.PrewhereCond, query)
+	}
+	return b
+}
+
+// LimitBy keeps the first n rows of each group of col with `LIMIT n BY` in clickhouse.
+// Like GroupBy, col is written as raw SQL without quotes.
+func (b *SelectStmt) LimitBy(n uint64, col ...string) *SelectStmt {
+	b.LimitByCount = int64(n)
+	b.LimitByColumn = col
+	return b
+}
+
+// Settings adds a query level setting with `SETTINGS` in clickhouse.
+// name must be an identifier of letters, digits and underscores.
+func (b *SelectStmt) Settings(name string, value interface{}) *SelectStmt {
+	if !settingName.MatchString(name) {
+		b.Setting = append(b.Setting, BuildFunc(func(Dialect, Buffer) error {
+			return ErrInvalidSetting
+		}))
+		return b
+	}
+	b.Setting = append(b.Setting, Expr(name+" = ?", value))
+	return b
+}
+
+// settingName is the names of settings that are safe to use without quotes.
+var settingName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (b *SelectStmt) buildFinalSample(d Dialect, buf Buffer) error {
+	if !b.IsFinal && b.SampleRatio <= 0 {
+		return nil
+	}
+	if d != dialect.ClickHouse {
+		return ErrNotSupported
+	}
+	if b.IsFinal {
+		buf.WriteString(" FINAL")
+	}
+	if b.SampleRatio > 0 {
+		buf.WriteString(" SAMPLE ")
+		buf.WriteString(strconv.FormatFloat(b.SampleRatio, 'f', -1, 64))
+	}
+	return nil
+}
+
+func (b *SelectStmt) buildPrewhere(d Dialect, buf Buffer) error {
+	if d != dialect.ClickHouse {
+		return ErrNotSupported
+	}
+	buf.WriteString(" PREWHERE ")
+	return And(b.PrewhereCond...).Build(d, buf)
+}
+
+func (b *SelectStmt) buildLimitBy(d Dialect, buf Buffer) error {
+	if d != dialect.ClickHouse {
+		return ErrNotSupported
+	}
+	buf.WriteString(" LIMIT ")
+	buf.WriteString(strconv.FormatInt(b.LimitByCount, 10))
+	buf.WriteString(" BY ")
+	buf.WriteString(strings.Join(b.LimitByColumn, ", "))
+	return nil
+}
+
+func (b *SelectStmt) buildSettings(d Dialect, buf Buffer) error {
+	if d != dialect.ClickHouse {
+		return ErrNotSupported
+	}
+	buf.WriteString(" SETTINGS ")
+	for i, setting := range b.Setting {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		err := setting.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package dbr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseSelectStmt(t *testing.T) {
+	builder := Select("user_id", "count()").
+		From("events").
+		Final().
+		Sample(0.1).
+		Prewhere(Eq("event_date", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))).
+		Where(Eq("name", "it's")).
+		GroupBy("user_id").
+		OrderDesc("count()").
+		LimitBy(2, "user_id").
+		Limit(10).
+		Settings("max_threads", 8).
+		Settings("join_use_nulls", true)
+
+	s, err := InterpolateForDialect("?", []interface{}{builder}, dialect.ClickHouse)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT user_id, count() FROM events FINAL SAMPLE 0.1 "+
+		"PREWHERE (`event_date` = toDateTime64('2020-01-02 03:04:05.000000', 6, 'UTC')) "+
+		"WHERE (`name` = 'it\\'s') GROUP BY user_id ORDER BY count() DESC LIMIT 2 BY user_id LIMIT 10 "+
+		"SETTINGS max_threads = 8, join_use_nulls = 1", s)
+
+	for _, builder := range []*SelectStmt{
+		Select("a").From("t").Final(),
+		Select("a").From("t").Sample(0.5),
+		Select("a").From("t").Prewhere("a = 1"),
+		Select("a").From("t").LimitBy(1, "a"),
+		Select("a").From("t").Settings("max_threads", 1),
+	} {
+		_, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+		require.Equal(t, ErrNotSupported, err)
+	}
+
+	_, err = InterpolateForDialect("?", []interface{}{
+		Select("a").From("t").Settings("max_threads = 1; DROP TABLE t --", 1),
+	}, dialect.ClickHouse)
+	require.Equal(t, ErrInvalidSetting, err)
+}
+
+func TestClickHouseMutation(t *testing.T) {
+	for _, test := range []struct {
+		builder Builder
+		want    string
+	}{
+		{
+			builder: Update("events").Set("name", "a").Where(Eq("id", 1)),
+			want:    "ALTER TABLE `events` UPDATE `name` = 'a' WHERE (`id` = 1)",
+		},
+		{
+			builder: Update("events").Set("name", "a"),
+			want:    "ALTER TABLE `events` UPDATE `name` = 'a' WHERE 1",
+		},
+		{
+			builder: DeleteFrom("events").Where(Eq("id", []int{1, 2})),
+			want:    "ALTER TABLE `events` DELETE WHERE (`id` IN (1,2))",
+		},
+		{
+			builder: DeleteFrom("events"),
+			want:    "ALTER TABLE `events` DELETE WHERE 1",
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.builder}, dialect.ClickHouse)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+	}
+
+	_, err := InterpolateForDialect("?", []interface{}{Update("events").Set("name", "a").Limit(1)}, dialect.ClickHouse)
+	require.Equal(t, ErrNotSupported, err)
+	_, err = InterpolateForDialect("?", []interface{}{DeleteFrom("events").Limit(1)}, dialect.ClickHouse)
+	require.Equal(t, ErrNotSupported, err)
+	_, err = InterpolateForDialect("?", []interface{}{Update("events").Set("name", "a").Returning("id")}, dialect.ClickHouse)
+	require.Equal(t, ErrNotSupported, err)
+	_, err = InterpolateForDialect("?", []interface{}{InsertInto("events").Columns("name").Values("a").Returning("id")}, dialect.ClickHouse)
+	require.Equal(t, ErrNotSupported, err)
+}
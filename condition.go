@@ -60,7 +60,7 @@ func Eq(column string, value interface{}) Builder {
 		v := reflect.ValueOf(value)
 		if v.Kind() == reflect.Slice {
 			if v.Len() == 0 {
-				buf.WriteString(d.EncodeBool(false))
+				buf.WriteString(encodeBoolCondition(d, false))
 				return nil
 			}
 			return buildCmp(d, buf, "IN", column, value)
@@ -83,7 +83,7 @@ func Neq(column string, value interface{}) Builder {
 		v := reflect.ValueOf(value)
 		if v.Kind() == reflect.Slice {
 			if v.Len() == 0 {
-				buf.WriteString(d.EncodeBool(true))
+				buf.WriteString(encodeBoolCondition(d, true))
 				return nil
 			}
 			return buildCmp(d, buf, "NOT IN", column, value)
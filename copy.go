@@ -0,0 +1,384 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// CopySource provides rows for CopyStmt one at a time.
+type CopySource interface {
+	// Next advances to the next row. It returns false at the end or on error.
+	Next() bool
+	// Values returns the values of the current row in the order of Columns.
+	Values() ([]interface{}, error)
+	// Err returns the error that stopped Next, if any.
+	Err() error
+}
+
+// CopyStmt loads rows into a PostgreSQL table with `COPY ... FROM STDIN`,
+// which is much faster than INSERT for large data sets.
+//
+// It requires the lib/pq driver, which implements COPY through prepared statements.
+type CopyStmt struct {
+	EventReceiver
+	Dialect
+
+	Table  string
+	Column []string
+	Source CopySource
+
+	sess    *Session
+	tx      *Tx
+	timeout time.Duration
+}
+
+// CopyFrom creates a CopyStmt.
+// As COPY requires a transaction, one is started for the copy.
+func (sess *Session) CopyFrom(table string) *CopyStmt {
+	return &CopyStmt{
+		EventReceiver: sess.EventReceiver,
+		Dialect:       sess.Dialect,
+		Table:         table,
+		sess:          sess,
+		timeout:       sess.GetTimeout(),
+	}
+}
+
+// CopyFrom creates a CopyStmt that copies in tx.
+func (tx *Tx) CopyFrom(table string) *CopyStmt {
+	return &CopyStmt{
+		EventReceiver: tx.EventReceiver,
+		Dialect:       tx.Dialect,
+		Table:         table,
+		tx:            tx,
+		timeout:       tx.GetTimeout(),
+	}
+}
+
+func (b *CopyStmt) Columns(column ...string) *CopyStmt {
+	b.Column = column
+	return b
+}
+
+// Records copies each element of slice.
+// Elements can be structs, whose fields are matched to Columns like Load,
+// or slices of values in the order of Columns. Unlike Load, a column
+// that matches no field of the struct is an error with ErrFieldNotFound.
+func (b *CopyStmt) Records(slice interface{}) *CopyStmt {
+	b.Source = &sliceCopySource{
+		v:      reflect.Indirect(reflect.ValueOf(slice)),
+		column: &b.Column,
+		i:      -1,
+	}
+	return b
+}
+
+// Rows copies rows from src, which can stream rows that are not in memory.
+func (b *CopyStmt) Rows(src CopySource) *CopyStmt {
+	b.Source = src
+	return b
+}
+
+// CSV copies records from r.
+// If Columns is not set, the first record is read as the header.
+func (b *CopyStmt) CSV(r io.Reader) *CopyStmt {
+	b.Source = &csvCopySource{
+		r:      csv.NewReader(r),
+		column: &b.Column,
+	}
+	return b
+}
+
+// Build writes `COPY ... FROM STDIN`.
+func (b *CopyStmt) Build(d Dialect, buf Buffer) error {
+	if d != dialect.PostgreSQL {
+		return ErrNotSupported
+	}
+	if b.Table == "" {
+		return ErrTableNotSpecified
+	}
+	if len(b.Column) == 0 {
+		return ErrColumnNotSpecified
+	}
+
+	buf.WriteString("COPY ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(" (")
+	for i, col := range b.Column {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	buf.WriteString(") FROM STDIN")
+	return nil
+}
+
+func (b *CopyStmt) Exec() (int64, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext copies all rows from Source, and returns the number of rows copied.
+func (b *CopyStmt) ExecContext(ctx context.Context) (int64, error) {
+	if b.timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	if b.Source == nil {
+		return 0, nil
+	}
+	if csvSource, ok := b.Source.(*csvCopySource); ok && len(b.Column) == 0 {
+		err := csvSource.readHeader()
+		if err != nil {
+			return 0, b.EventErr("dbr.copy.csv", err)
+		}
+	}
+
+	buf := NewBuffer()
+	err := b.Build(b.Dialect, buf)
+	query := buf.String()
+	if err != nil {
+		return 0, b.EventErrKv("dbr.copy.build", err, kvs{
+			"table": b.Table,
+		})
+	}
+
+	tx := b.tx
+	if tx == nil {
+		tx, err = b.sess.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, b.EventErrKv("dbr.copy.begin", err, kvs{
+				"table": b.Table,
+			})
+		}
+		defer tx.RollbackUnlessCommitted()
+	}
+
+	startTime := time.Now()
+	defer func() {
+		b.TimingKv("dbr.copy", time.Since(startTime).Nanoseconds(), kvs{
+			"sql": query,
+		})
+	}()
+
+	traceImpl, hasTracingImpl := b.EventReceiver.(TracingEventReceiver)
+	if hasTracingImpl {
+		ctx = traceImpl.SpanStart(ctx, "dbr.copy", query)
+		defer traceImpl.SpanFinish(ctx)
+	}
+
+	count, err := b.copy(ctx, tx.Tx, query)
+	if err != nil {
+		if hasTracingImpl {
+			traceImpl.SpanError(ctx, err)
+		}
+		return 0, b.EventErrKv("dbr.copy.exec", err, kvs{
+			"sql": query,
+		})
+	}
+
+	if b.tx == nil {
+		err = tx.Commit()
+		if err != nil {
+			return 0, b.EventErrKv("dbr.copy.commit", err, kvs{
+				"sql": query,
+			})
+		}
+	}
+	return count, nil
+}
+
+func (b *CopyStmt) copy(ctx context.Context, tx *sql.Tx, query string) (int64, error) {
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var count int64
+	for b.Source.Next() {
+		value, err := b.Source.Values()
+		if err != nil {
+			return 0, err
+		}
+		_, err = stmt.ExecContext(ctx, value...)
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err := b.Source.Err(); err != nil {
+		return 0, err
+	}
+
+	// flush the COPY stream
+	_, err = stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+type sliceCopySource struct {
+	v      reflect.Value
+	column *[]string
+	i      int
+	ts     *tagStore
+	// checked is the struct type whose fields match all columns.
+	checked reflect.Type
+}
+
+func (s *sliceCopySource) Next() bool {
+	if s.v.Kind() != reflect.Slice && s.v.Kind() != reflect.Array {
+		return false
+	}
+	s.i++
+	return s.i < s.v.Len()
+}
+
+func (s *sliceCopySource) Values() ([]interface{}, error) {
+	elem := s.v.Index(s.i)
+	if elem.Kind() == reflect.Interface {
+		elem = elem.Elem()
+	}
+	if reflect.Indirect(elem).Kind() == reflect.Struct {
+		if s.ts == nil {
+			s.ts = newTagStore()
+		}
+		if elem.Type() != s.checked {
+			err := s.checkColumns(elem.Type())
+			if err != nil {
+				return nil, err
+			}
+			s.checked = elem.Type()
+		}
+		found := make([]interface{}, len(*s.column))
+		s.ts.findValueByName(elem, *s.column, found, false)
+		for i, v := range found {
+			if v == nil {
+				continue
+			}
+			v := v.(reflect.Value)
+			if isArrayType(v.Type()) {
+				// COPY text format is the same as array text format
+				found[i] = Array(v.Interface())
+			} else {
+				found[i] = v.Interface()
+			}
+		}
+		return found, nil
+	}
+	if elem.Kind() == reflect.Slice {
+		value := make([]interface{}, elem.Len())
+		for i := range value {
+			value[i] = elem.Index(i).Interface()
+		}
+		return value, nil
+	}
+	return nil, ErrNotSupported
+}
+
+// checkColumns returns ErrFieldNotFound if a column matches no field of t,
+// so that a misspelled column is not copied as NULL.
+func (s *sliceCopySource) checkColumns(t reflect.Type) error {
+	found := make([]bool, len(*s.column))
+	s.matchFields(t, *s.column, found, map[reflect.Type]bool{})
+	for _, ok := range found {
+		if !ok {
+			return ErrFieldNotFound
+		}
+	}
+	return nil
+}
+
+// matchFields marks the names that fields of t are tagged with, like findValueByName
+// with the types of fields, so that nil pointers to structs are still matched.
+func (s *sliceCopySource) matchFields(t reflect.Type, name []string, found []bool, visiting map[reflect.Type]bool) {
+	if t.Implements(typeValuer) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		s.matchFields(t.Elem(), name, found, visiting)
+	case reflect.Struct:
+		if visiting[t] {
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		l := s.ts.get(t)
+		for i := 0; i < t.NumField(); i++ {
+			tag := l[i]
+			if tag == "" {
+				continue
+			}
+			for j, want := range name {
+				if want == tag {
+					found[j] = true
+				}
+			}
+			fieldType := t.Field(i).Type
+			s.matchFields(fieldType, name, found, visiting)
+			switch fieldType.Kind() {
+			case reflect.Struct, reflect.Ptr:
+				// columns like tag.name are only for this field.
+				if sub := trimPrefix(name, tag); sub != nil {
+					s.matchFields(fieldType, sub, found, visiting)
+				}
+			}
+		}
+	}
+}
+
+func (s *sliceCopySource) Err() error {
+	if s.v.Kind() != reflect.Slice && s.v.Kind() != reflect.Array {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+type csvCopySource struct {
+	r      *csv.Reader
+	column *[]string
+	record []string
+	err    error
+}
+
+func (s *csvCopySource) readHeader() error {
+	header, err := s.r.Read()
+	if err != nil {
+		return err
+	}
+	*s.column = header
+	return nil
+}
+
+func (s *csvCopySource) Next() bool {
+	s.record, s.err = s.r.Read()
+	if s.err == io.EOF {
+		s.err = nil
+		return false
+	}
+	return s.err == nil
+}
+
+func (s *csvCopySource) Values() ([]interface{}, error) {
+	value := make([]interface{}, len(s.record))
+	for i, field := range s.record {
+		value[i] = field
+	}
+	return value, nil
+}
+
+func (s *csvCopySource) Err() error {
+	return s.err
+}
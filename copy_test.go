@@ -0,0 +1,147 @@
+package dbr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyStmt(t *testing.T) {
+	buf := NewBuffer()
+	err := (&CopyStmt{Table: "public.dbr_people", Column: []string{"name", "email"}}).Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `COPY "public"."dbr_people" ("name", "email") FROM STDIN`, buf.String())
+
+	err = (&CopyStmt{Table: "dbr_people", Column: []string{"name"}}).Build(dialect.MySQL, NewBuffer())
+	require.Equal(t, ErrNotSupported, err)
+}
+
+func TestCopyFrom(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.PostgreSQL,
+	}
+	sess := conn.NewSession(nil)
+
+	// session starts its own transaction
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`COPY "dbr_people" \("name", "email"\) FROM STDIN`)
+	prepare.ExpectExec().WithArgs("a", "a@example.com").WillReturnResult(sqlmock.NewResult(0, 0))
+	prepare.ExpectExec().WithArgs("b", "b@example.com").WillReturnResult(sqlmock.NewResult(0, 0))
+	prepare.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	n, err := sess.CopyFrom("dbr_people").
+		Columns("name", "email").
+		Records([]dbrPerson{
+			{Name: "a", Email: "a@example.com"},
+			{Name: "b", Email: "b@example.com"},
+		}).
+		Exec()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	// CSV within a transaction
+	mock.ExpectBegin()
+	prepare = mock.ExpectPrepare(`COPY "dbr_people" \("name", "email"\) FROM STDIN`)
+	prepare.ExpectExec().WithArgs("c", "c@example.com").WillReturnResult(sqlmock.NewResult(0, 0))
+	prepare.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	n, err = tx.CopyFrom("dbr_people").
+		CSV(strings.NewReader("name,email\nc,c@example.com\n")).
+		Exec()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCopyFromError(t *testing.T) {
+	log := &testEventReceiver{}
+	sess, mock := newMockSession(t, dialect.PostgreSQL, log)
+
+	// a column that matches no field is not copied as NULL
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`COPY "dbr_people" ("name", "mail") FROM STDIN`)
+	prepare.WillBeClosed()
+	mock.ExpectRollback()
+
+	_, err := sess.CopyFrom("dbr_people").
+		Columns("name", "mail").
+		Records([]dbrPerson{{Name: "a", Email: "a@example.com"}}).
+		Exec()
+	require.Equal(t, ErrFieldNotFound, err)
+	require.Equal(t, []string{"dbr.copy.exec"}, log.events)
+	log.events = nil
+
+	// failing to begin or commit is reported
+	boom := errors.New("boom")
+	mock.ExpectBegin().WillReturnError(boom)
+	_, err = sess.CopyFrom("numbers").Columns("n").Rows(&testCopySource{n: 1}).Exec()
+	require.ErrorIs(t, err, boom)
+
+	mock.ExpectBegin()
+	prepare = mock.ExpectPrepare(`COPY "numbers" ("n") FROM STDIN`)
+	prepare.ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	prepare.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(boom)
+	_, err = sess.CopyFrom("numbers").Columns("n").Rows(&testCopySource{n: 1}).Exec()
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, []string{"dbr.copy.begin", "dbr.copy.commit"}, log.events)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type testCopySource struct {
+	n, i int
+}
+
+func (s *testCopySource) Next() bool {
+	s.i++
+	return s.i <= s.n
+}
+
+func (s *testCopySource) Values() ([]interface{}, error) {
+	return []interface{}{s.i}, nil
+}
+
+func (s *testCopySource) Err() error {
+	return nil
+}
+
+func TestCopyFromSource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.PostgreSQL,
+	}
+	sess := conn.NewSession(nil)
+
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`COPY "numbers" \("n"\) FROM STDIN`)
+	for i := 1; i <= 3; i++ {
+		prepare.ExpectExec().WithArgs(i).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	prepare.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	n, err := sess.CopyFrom("numbers").Columns("n").Rows(&testCopySource{n: 3}).Exec()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
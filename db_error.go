@@ -0,0 +1,276 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// DBError is an error from the database driver that dbr has classified.
+//
+// It matches its Kind with errors.Is, like
+//
+//	if errors.Is(err, dbr.ErrUniqueViolation) {
+//		// ...
+//	}
+//
+// and the driver error can still be found with errors.As.
+type DBError struct {
+	// Kind is one of the database errors, like ErrUniqueViolation.
+	Kind error
+	// Code is the error code of the driver, like SQLSTATE of postgres.
+	Code string
+	// Constraint and Column are the names in the error,
+	// if the driver exposes them.
+	Constraint string
+	Column     string
+	Err        error
+}
+
+func (e *DBError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the driver error.
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of e.
+func (e *DBError) Is(target error) bool {
+	return target == e.Kind
+}
+
+var (
+	mysqlErrors = map[string]error{
+		"1062": ErrUniqueViolation,
+		"1586": ErrUniqueViolation,
+		"1216": ErrForeignKeyViolation,
+		"1217": ErrForeignKeyViolation,
+		"1451": ErrForeignKeyViolation,
+		"1452": ErrForeignKeyViolation,
+		"1048": ErrNotNullViolation,
+		"1364": ErrNotNullViolation,
+		"3819": ErrCheckViolation,
+		"1213": ErrDeadlock,
+		"1205": ErrLockTimeout,
+		"1317": ErrQueryCanceled,
+		"3024": ErrQueryCanceled,
+		"2006": ErrConnectionLost,
+		"2013": ErrConnectionLost,
+	}
+	postgresErrors = map[string]error{
+		"23505": ErrUniqueViolation,
+		"23503": ErrForeignKeyViolation,
+		"23502": ErrNotNullViolation,
+		"23514": ErrCheckViolation,
+		"40P01": ErrDeadlock,
+		"40001": ErrSerializationFailure,
+		"55P03": ErrLockTimeout,
+		"57014": ErrQueryCanceled,
+		"57P01": ErrConnectionLost,
+	}
+	// extended result codes, and then primary result codes
+	sqlite3Errors = map[string]error{
+		"2067": ErrUniqueViolation,
+		"1555": ErrUniqueViolation,
+		"787":  ErrForeignKeyViolation,
+		"1299": ErrNotNullViolation,
+		"275":  ErrCheckViolation,
+		"517":  ErrSerializationFailure,
+		"5":    ErrLockTimeout,
+		"6":    ErrLockTimeout,
+		"9":    ErrQueryCanceled,
+	}
+	mssqlErrors = map[string]error{
+		"2627": ErrUniqueViolation,
+		"2601": ErrUniqueViolation,
+		"515":  ErrNotNullViolation,
+		"1205": ErrDeadlock,
+		"3960": ErrSerializationFailure,
+		"1222": ErrLockTimeout,
+		"3617": ErrQueryCanceled,
+	}
+	oracleErrors = map[string]error{
+		"1":     ErrUniqueViolation,
+		"2291":  ErrForeignKeyViolation,
+		"2292":  ErrForeignKeyViolation,
+		"1400":  ErrNotNullViolation,
+		"2290":  ErrCheckViolation,
+		"60":    ErrDeadlock,
+		"8177":  ErrSerializationFailure,
+		"54":    ErrLockTimeout,
+		"30006": ErrLockTimeout,
+		"1013":  ErrQueryCanceled,
+		"3113":  ErrConnectionLost,
+		"3114":  ErrConnectionLost,
+		"3135":  ErrConnectionLost,
+	}
+)
+
+// classifyError wraps err in DBError if it is a known database error in dialect d.
+func classifyError(d Dialect, err error) error {
+	if err == nil {
+		return nil
+	}
+	var dbErr *DBError
+	if errors.As(err, &dbErr) {
+		return err
+	}
+
+	code := driverErrorCode(err)
+	var kind error
+	switch d {
+	case dialect.MySQL:
+		kind = mysqlErrors[code]
+	case dialect.PostgreSQL:
+		kind = postgresErrors[code]
+		if kind == nil && strings.HasPrefix(code, "08") {
+			// connection_exception
+			kind = ErrConnectionLost
+		}
+	case dialect.SQLite3:
+		if extended := driverErrorField(err, "ExtendedCode"); sqlite3Errors[extended] != nil {
+			code = extended
+		}
+		kind = sqlite3Errors[code]
+	case dialect.MSSQL:
+		kind = mssqlErrors[code]
+		if code == "547" {
+			// both foreign key and check constraints
+			kind = ErrForeignKeyViolation
+			if strings.Contains(err.Error(), "CHECK constraint") {
+				kind = ErrCheckViolation
+			}
+		}
+	case dialect.Oracle:
+		kind = oracleErrors[code]
+	}
+	if kind == nil {
+		switch {
+		case errors.Is(err, driver.ErrBadConn):
+			kind = ErrConnectionLost
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			kind = ErrQueryCanceled
+		default:
+			return err
+		}
+	}
+
+	e := &DBError{
+		Kind:       kind,
+		Code:       code,
+		Constraint: driverErrorField(err, "Constraint", "ConstraintName"),
+		Column:     driverErrorField(err, "Column", "ColumnName"),
+		Err:        err,
+	}
+	if e.Constraint == "" {
+		e.Constraint = matchError(err, constraintPattern)
+	}
+	if e.Column == "" {
+		e.Column = matchError(err, columnPattern)
+	}
+	return e
+}
+
+// Drivers other than postgres only have names in the error message.
+var (
+	constraintPattern = []*regexp.Regexp{
+		// mysql
+		regexp.MustCompile(`for key '(?:[^'.]+\.)?([^']+)'`),
+		regexp.MustCompile("CONSTRAINT `([^`]+)`"),
+		regexp.MustCompile(`Check constraint '([^']+)' is violated`),
+		// sqlite3
+		regexp.MustCompile(`CHECK constraint failed: ([^\s,]+)`),
+		// mssql
+		regexp.MustCompile(`constraint ["']([^"']+)["']`),
+		regexp.MustCompile(`with unique index '([^']+)'`),
+		// oracle
+		regexp.MustCompile(`constraint \((?:[^.)]+\.)?([^)]+)\) violated`),
+	}
+	columnPattern = []*regexp.Regexp{
+		// mysql
+		regexp.MustCompile("FOREIGN KEY \\(`([^`]+)`"),
+		regexp.MustCompile(`Column '([^']+)' cannot be null`),
+		regexp.MustCompile(`Field '([^']+)' doesn't have a default value`),
+		// sqlite3
+		regexp.MustCompile(`(?:UNIQUE|NOT NULL) constraint failed: [^.\s]+\.([^\s,]+)`),
+		// mssql
+		regexp.MustCompile(`NULL into column '([^']+)'`),
+		// oracle
+		regexp.MustCompile(`NULL into \((?:"[^"]+"\.)*"([^"]+)"\)`),
+	}
+)
+
+func matchError(err error, pattern []*regexp.Regexp) string {
+	msg := err.Error()
+	for _, re := range pattern {
+		if m := re.FindStringSubmatch(msg); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// driverErrorCode returns the code of the first driver error in the chain of err,
+// like SQLSTATE of postgres, or the error number of mysql, mssql, sqlite3 and oracle.
+// Drivers are not imported, so errors are inspected by their methods and fields.
+func driverErrorCode(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case interface{ SQLState() string }:
+			return e.SQLState()
+		case interface{ SQLErrorNumber() int32 }:
+			return strconv.Itoa(int(e.SQLErrorNumber()))
+		case interface{ Code() int }:
+			return strconv.Itoa(e.Code())
+		}
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		// Number of mysql, Code of postgres and sqlite3
+		for _, name := range []string{"Number", "Code"} {
+			if s := fieldString(v.FieldByName(name)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// fieldString formats a string or integer field, or returns "" for other kinds.
+func fieldString(f reflect.Value) string {
+	switch f.Kind() {
+	case reflect.String:
+		return f.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(f.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(f.Uint(), 10)
+	}
+	return ""
+}
+
+// driverErrorField returns the first non-empty field with one of names
+// in the chain of err.
+func driverErrorField(err error, names ...string) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		for _, name := range names {
+			if s := fieldString(v.FieldByName(name)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
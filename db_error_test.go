@@ -0,0 +1,205 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+type oracleError struct {
+	code    int
+	message string
+}
+
+func (e *oracleError) Code() int {
+	return e.code
+}
+
+func (e *oracleError) Error() string {
+	return e.message
+}
+
+func TestClassifyError(t *testing.T) {
+	for _, test := range []struct {
+		d          Dialect
+		err        error
+		kind       error
+		constraint string
+		column     string
+	}{
+		{
+			d:          dialect.MySQL,
+			err:        &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'dbr_people.email'"},
+			kind:       ErrUniqueViolation,
+			constraint: "email",
+		},
+		{
+			d:          dialect.MySQL,
+			err:        &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`pet`, CONSTRAINT `fk_owner` FOREIGN KEY (`owner_id`) REFERENCES `dbr_people` (`id`))"},
+			kind:       ErrForeignKeyViolation,
+			constraint: "fk_owner",
+			column:     "owner_id",
+		},
+		{
+			d:      dialect.MySQL,
+			err:    &mysql.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"},
+			kind:   ErrNotNullViolation,
+			column: "name",
+		},
+		{
+			d:          dialect.MySQL,
+			err:        &mysql.MySQLError{Number: 3819, Message: "Check constraint 'age_positive' is violated."},
+			kind:       ErrCheckViolation,
+			constraint: "age_positive",
+		},
+		{
+			d:    dialect.MySQL,
+			err:  &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"},
+			kind: ErrLockTimeout,
+		},
+		{
+			d:          dialect.PostgreSQL,
+			err:        &pq.Error{Code: "23505", Constraint: "dbr_people_email_key"},
+			kind:       ErrUniqueViolation,
+			constraint: "dbr_people_email_key",
+		},
+		{
+			d:      dialect.PostgreSQL,
+			err:    fmt.Errorf("insert: %w", &pq.Error{Code: "23502", Column: "name"}),
+			kind:   ErrNotNullViolation,
+			column: "name",
+		},
+		{
+			d:    dialect.PostgreSQL,
+			err:  &pq.Error{Code: "40001"},
+			kind: ErrSerializationFailure,
+		},
+		{
+			d:    dialect.PostgreSQL,
+			err:  &pq.Error{Code: "08006"},
+			kind: ErrConnectionLost,
+		},
+		{
+			d:    dialect.PostgreSQL,
+			err:  &pq.Error{Code: "57014"},
+			kind: ErrQueryCanceled,
+		},
+		{
+			d:    dialect.SQLite3,
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique},
+			kind: ErrUniqueViolation,
+		},
+		{
+			d:    dialect.SQLite3,
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey},
+			kind: ErrForeignKeyViolation,
+		},
+		{
+			d:    dialect.SQLite3,
+			err:  sqlite3.Error{Code: sqlite3.ErrBusy},
+			kind: ErrLockTimeout,
+		},
+		{
+			d:          dialect.MSSQL,
+			err:        mssql.Error{Number: 2627, Message: "Violation of UNIQUE KEY constraint 'UQ_email'. Cannot insert duplicate key in object 'dbo.dbr_people'."},
+			kind:       ErrUniqueViolation,
+			constraint: "UQ_email",
+		},
+		{
+			d:          dialect.MSSQL,
+			err:        mssql.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "CK_age".`},
+			kind:       ErrCheckViolation,
+			constraint: "CK_age",
+		},
+		{
+			d:          dialect.MSSQL,
+			err:        mssql.Error{Number: 547, Message: `The INSERT statement conflicted with the FOREIGN KEY constraint "FK_owner".`},
+			kind:       ErrForeignKeyViolation,
+			constraint: "FK_owner",
+		},
+		{
+			d:      dialect.MSSQL,
+			err:    mssql.Error{Number: 515, Message: "Cannot insert the value NULL into column 'name', table 'db.dbo.dbr_people'; column does not allow nulls."},
+			kind:   ErrNotNullViolation,
+			column: "name",
+		},
+		{
+			d:          dialect.Oracle,
+			err:        &oracleError{code: 1, message: "ORA-00001: unique constraint (DBR.UQ_EMAIL) violated"},
+			kind:       ErrUniqueViolation,
+			constraint: "UQ_EMAIL",
+		},
+		{
+			d:      dialect.Oracle,
+			err:    &oracleError{code: 1400, message: `ORA-01400: cannot insert NULL into ("DBR"."DBR_PEOPLE"."NAME")`},
+			kind:   ErrNotNullViolation,
+			column: "NAME",
+		},
+		{
+			d:    dialect.MySQL,
+			err:  driver.ErrBadConn,
+			kind: ErrConnectionLost,
+		},
+		{
+			d:    dialect.PostgreSQL,
+			err:  context.Canceled,
+			kind: ErrQueryCanceled,
+		},
+	} {
+		err := classifyError(test.d, test.err)
+		require.ErrorIs(t, err, test.kind, "%v", test.err)
+
+		var dbErr *DBError
+		require.True(t, errors.As(err, &dbErr))
+		require.Equal(t, test.err, errors.Unwrap(err))
+		require.Equal(t, test.constraint, dbErr.Constraint, "%v", test.err)
+		require.Equal(t, test.column, dbErr.Column, "%v", test.err)
+	}
+
+	// unknown errors are not wrapped
+	for _, err := range []error{
+		&mysql.MySQLError{Number: 1064},
+		&pq.Error{Code: "42601"},
+		ErrNotFound,
+	} {
+		require.Equal(t, err, classifyError(dialect.PostgreSQL, err))
+	}
+}
+
+func TestSQLMockDBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+
+	mock.ExpectExec("INSERT INTO `dbr_people`").WillReturnError(&mysql.MySQLError{
+		Number:  1062,
+		Message: "Duplicate entry 'a@b.c' for key 'email'",
+	})
+	_, err = sess.InsertInto("dbr_people").Pair("email", "a@b.c").Exec()
+	require.ErrorIs(t, err, ErrUniqueViolation)
+	var mysqlErr *mysql.MySQLError
+	require.True(t, errors.As(err, &mysqlErr))
+
+	mock.ExpectQuery("SELECT").WillReturnError(&mysql.MySQLError{Number: 1317})
+	var name string
+	err = sess.Select("name").From("dbr_people").LoadOne(&name)
+	require.ErrorIs(t, err, ErrQueryCanceled)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(&mysql.MySQLError{Number: 1213})
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	require.ErrorIs(t, tx.Commit(), ErrDeadlock)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,35 +5,66 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gocraft/dbr/v2/dialect"
 )
 
+var (
+	dialectMu sync.RWMutex
+	dialects  = map[string]Dialect{
+		"mysql":      dialect.MySQL,
+		"postgres":   dialect.PostgreSQL,
+		"pgx":        dialect.PostgreSQL,
+		"sqlite3":    dialect.SQLite3,
+		"sqlite":     dialect.SQLite3,
+		"mssql":      dialect.MSSQL,
+		"sqlserver":  dialect.MSSQL,
+		"godror":     dialect.Oracle,
+		"oracle":     dialect.Oracle,
+		"clickhouse": dialect.ClickHouse,
+	}
+)
+
+// RegisterDialect makes d the Dialect of connections that Open creates
+// with driver, like a driver alias or an instrumented wrapper driver.
+// It replaces the Dialect that is already registered for driver.
+func RegisterDialect(driver string, d Dialect) {
+	dialectMu.Lock()
+	defer dialectMu.Unlock()
+	dialects[driver] = d
+}
+
+func lookupDialect(driver string) (Dialect, bool) {
+	dialectMu.RLock()
+	defer dialectMu.RUnlock()
+	d, ok := dialects[driver]
+	return d, ok
+}
+
 // Open creates a Connection.
+// driver must have a Dialect registered with RegisterDialect.
 // log can be nil to ignore logging.
 func Open(driver, dsn string, log EventReceiver) (*Connection, error) {
-	if log == nil {
-		log = nullReceiver
+	d, ok := lookupDialect(driver)
+	if !ok {
+		return nil, ErrNotSupported
 	}
 	conn, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
-	var d Dialect
-	switch driver {
-	case "mysql":
-		d = dialect.MySQL
-	case "postgres", "pgx":
-		d = dialect.PostgreSQL
-	case "sqlite3", "sqlite":
-		d = dialect.SQLite3
-	case "mssql":
-		d = dialect.MSSQL
-	default:
-		return nil, ErrNotSupported
+	return NewConnection(conn, d, log), nil
+}
+
+// NewConnection creates a Connection from db that is already opened.
+// log can be nil to ignore logging.
+func NewConnection(db *sql.DB, d Dialect, log EventReceiver) *Connection {
+	if log == nil {
+		log = nullReceiver
 	}
-	return &Connection{DB: conn, EventReceiver: log, Dialect: d}, nil
+	return &Connection{DB: db, EventReceiver: log, Dialect: d}
 }
 
 const (
@@ -42,10 +73,17 @@ const (
 
 // Connection wraps sql.DB with an EventReceiver
 // to send events, errors, and timings.
+//
+// If StmtCache is set, sessions run queries through cached prepared statements.
+// If BindParams is set, sessions send values as bind parameters.
+// If Replicas is set, sessions read from replicas.
 type Connection struct {
 	*sql.DB
 	Dialect
 	EventReceiver
+	StmtCache  *StmtCache
+	BindParams bool
+	Replicas   *ReplicaPool
 }
 
 // Session represents a business unit of execution.
@@ -57,10 +95,27 @@ type Connection struct {
 // A custom EventReceiver can be set.
 //
 // Timeout specifies max duration for an operation like Select.
+//
+// BindParams sends every value as a driver argument with dialect placeholders
+// instead of interpolating it into SQL. This keeps values out of the logged SQL,
+// and lets the driver encode types like time.Time or driver.Valuer natively.
+//
+// StmtCache enables prepared statement mode, where values are sent as
+// bind parameters of cached prepared statements instead of being interpolated.
+//
+// Replicas sends SelectStmt reads to read replicas.
+//
+// They default to the settings of Connection.
+//
+// TxRetry configures the retries of RunInTx.
 type Session struct {
 	*Connection
 	EventReceiver
-	Timeout time.Duration
+	Timeout    time.Duration
+	StmtCache  *StmtCache
+	BindParams bool
+	Replicas   *ReplicaPool
+	TxRetry    TxRetry
 }
 
 // GetTimeout returns current timeout enforced in session.
@@ -74,13 +129,20 @@ func (conn *Connection) NewSession(log EventReceiver) *Session {
 	if log == nil {
 		log = conn.EventReceiver // Use parent instrumentation
 	}
-	return &Session{Connection: conn, EventReceiver: log}
+	return &Session{
+		Connection:    conn,
+		EventReceiver: log,
+		StmtCache:     conn.StmtCache,
+		BindParams:    conn.BindParams,
+		Replicas:      conn.Replicas,
+	}
 }
 
 // Ensure that tx and session are session runner
 var (
 	_ SessionRunner = (*Tx)(nil)
 	_ SessionRunner = (*Session)(nil)
+	_ SessionRunner = (*ShardedSession)(nil)
 )
 
 // SessionRunner can do anything that a Session can except start a transaction.
@@ -99,13 +161,44 @@ type SessionRunner interface {
 	DeleteBySql(query string, value ...interface{}) *DeleteBuilder
 }
 
+// TxBeginner can start a transaction.
+// Both Session and Tx implements this interface, and Tx starts a nested transaction.
+type TxBeginner interface {
+	Begin() (*Tx, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
+}
+
 type Runner interface {
 	GetTimeout() time.Duration
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 }
 
+// paramBinder is implemented by runners that can send values as bind parameters.
+type paramBinder interface {
+	bindParams() bool
+}
+
+// useBindParams reports whether runner has bind parameter mode enabled.
+func useBindParams(runner Runner) bool {
+	b, ok := runner.(paramBinder)
+	return ok && b.bindParams()
+}
+
+func (sess *Session) bindParams() bool {
+	return sess.BindParams
+}
+
+func (tx *Tx) bindParams() bool {
+	return tx.BindParams
+}
+
 func exec(ctx context.Context, runner Runner, log EventReceiver, builder Builder, d Dialect) (sql.Result, error) {
+	runner, log, err := shardRunner(ctx, runner, log, builder)
+	if err != nil {
+		return nil, err
+	}
+
 	timeout := runner.GetTimeout()
 	if timeout > 0 {
 		var cancel func()
@@ -113,12 +206,14 @@ func exec(ctx context.Context, runner Runner, log EventReceiver, builder Builder
 		defer cancel()
 	}
 
+	prepared := usePreparedStmt(runner)
 	i := interpolator{
 		Buffer:       NewBuffer(),
 		Dialect:      d,
 		IgnoreBinary: true,
+		Bind:         prepared || useBindParams(runner),
 	}
-	err := i.encodePlaceholder(builder, true)
+	err = i.encodePlaceholder(builder, true)
 	query, value := i.String(), i.Value()
 	if err != nil {
 		return nil, log.EventErrKv("dbr.exec.interpolate", err, kvs{
@@ -140,11 +235,24 @@ func exec(ctx context.Context, runner Runner, log EventReceiver, builder Builder
 		defer traceImpl.SpanFinish(ctx)
 	}
 
-	result, err := runner.ExecContext(ctx, query, value...)
+	var result sql.Result
+	if prepared {
+		var stmt *sql.Stmt
+		var release func()
+		stmt, release, err = runner.(stmtPreparer).prepareContext(ctx, query)
+		if err == nil {
+			result, err = stmt.ExecContext(ctx, value...)
+			release()
+		}
+	} else {
+		result, err = runner.ExecContext(ctx, query, value...)
+	}
 	if err != nil {
+		err = classifyError(d, err)
 		if hasTracingImpl {
 			traceImpl.SpanError(ctx, err)
 		}
+		err = newQueryError("exec", builder, d, query, i.Bind, startTime, err)
 		return result, log.EventErrKv("dbr.exec.exec", err, kvs{
 			"sql": query,
 		})
@@ -156,10 +264,12 @@ func queryRows(ctx context.Context, runner Runner, log EventReceiver, builder Bu
 	// discard the timeout set in the runner, the context should not be canceled
 	// implicitly here but explicitly by the caller since the returned *sql.Rows
 	// may still listening to the context
+	prepared := usePreparedStmt(runner)
 	i := interpolator{
 		Buffer:       NewBuffer(),
 		Dialect:      d,
 		IgnoreBinary: true,
+		Bind:         prepared || useBindParams(runner),
 	}
 	err := i.encodePlaceholder(builder, true)
 	query, value := i.String(), i.Value()
@@ -183,11 +293,24 @@ func queryRows(ctx context.Context, runner Runner, log EventReceiver, builder Bu
 		defer traceImpl.SpanFinish(ctx)
 	}
 
-	rows, err := runner.QueryContext(ctx, query, value...)
+	var rows *sql.Rows
+	if prepared {
+		var stmt *sql.Stmt
+		var release func()
+		stmt, release, err = runner.(stmtPreparer).prepareContext(ctx, query)
+		if err == nil {
+			rows, err = stmt.QueryContext(ctx, value...)
+			release()
+		}
+	} else {
+		rows, err = runner.QueryContext(ctx, query, value...)
+	}
 	if err != nil {
+		err = classifyError(d, err)
 		if hasTracingImpl {
 			traceImpl.SpanError(ctx, err)
 		}
+		err = newQueryError("select", builder, d, query, i.Bind, startTime, err)
 		return query, nil, log.EventErrKv("dbr.select.load.query", err, kvs{
 			"sql": query,
 		})
@@ -197,6 +320,11 @@ func queryRows(ctx context.Context, runner Runner, log EventReceiver, builder Bu
 }
 
 func query(ctx context.Context, runner Runner, log EventReceiver, builder Builder, d Dialect, dest interface{}) (int, error) {
+	runner, log, err := shardRunner(ctx, runner, log, builder)
+	if err != nil {
+		return 0, err
+	}
+
 	timeout := runner.GetTimeout()
 	if timeout > 0 {
 		var cancel func()
@@ -204,12 +332,15 @@ func query(ctx context.Context, runner Runner, log EventReceiver, builder Builde
 		defer cancel()
 	}
 
+	startTime := time.Now()
 	query, rows, err := queryRows(ctx, runner, log, builder, d)
 	if err != nil {
 		return 0, err
 	}
 	count, err := Load(rows, dest)
 	if err != nil {
+		bound := usePreparedStmt(runner) || useBindParams(runner)
+		err = newQueryError("load", builder, d, query, bound, startTime, classifyError(d, err))
 		return 0, log.EventErrKv("dbr.select.load.scan", err, kvs{
 			"sql": query,
 		})
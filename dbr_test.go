@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gocraft/dbr/v2/dialect"
@@ -200,19 +201,19 @@ func TestTimeout(t *testing.T) {
 		sess.Timeout = time.Nanosecond
 		var people []dbrPerson
 		_, err := sess.Select("*").From("dbr_people").Load(&people)
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 		require.Equal(t, 1, sess.EventReceiver.(*testTraceReceiver).errored)
 
 		_, err = sess.InsertInto("dbr_people").Columns("name", "email").Values("test", "test@test.com").Exec()
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 		require.Equal(t, 2, sess.EventReceiver.(*testTraceReceiver).errored)
 
 		_, err = sess.Update("dbr_people").Set("name", "test1").Exec()
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 		require.Equal(t, 3, sess.EventReceiver.(*testTraceReceiver).errored)
 
 		_, err = sess.DeleteFrom("dbr_people").Exec()
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 		require.Equal(t, 4, sess.EventReceiver.(*testTraceReceiver).errored)
 
 		// tx op timeout
@@ -223,15 +224,55 @@ func TestTimeout(t *testing.T) {
 		tx.Timeout = time.Nanosecond
 
 		_, err = tx.Select("*").From("dbr_people").Load(&people)
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 
 		_, err = tx.InsertInto("dbr_people").Columns("name", "email").Values("test", "test@test.com").Exec()
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 
 		_, err = tx.Update("dbr_people").Set("name", "test1").Exec()
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 
 		_, err = tx.DeleteFrom("dbr_people").Exec()
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
 	}
 }
+
+func TestRegisterDialect(t *testing.T) {
+	_, err := Open("sqlmock", "dbr_register_dialect", nil)
+	require.Equal(t, ErrNotSupported, err)
+
+	db, mock, err := sqlmock.NewWithDSN("dbr_register_dialect")
+	require.NoError(t, err)
+	defer db.Close()
+
+	RegisterDialect("sqlmock", dialect.PostgreSQL)
+	defer func() {
+		dialectMu.Lock()
+		delete(dialects, "sqlmock")
+		dialectMu.Unlock()
+	}()
+
+	conn, err := Open("sqlmock", "dbr_register_dialect", nil)
+	require.NoError(t, err)
+	require.Equal(t, dialect.PostgreSQL, conn.Dialect)
+
+	mock.ExpectExec(`DELETE FROM "dbr_people" WHERE \("id" = 1\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = conn.NewSession(nil).DeleteFrom("dbr_people").Where(Eq("id", 1)).Exec()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewConnection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := NewConnection(db, dialect.MySQL, nil)
+	require.Equal(t, nullReceiver, conn.EventReceiver)
+
+	mock.ExpectExec("DELETE FROM `dbr_people` WHERE \\(`id` = 1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = conn.NewSession(nil).DeleteFrom("dbr_people").Where(Eq("id", 1)).Exec()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
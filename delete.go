@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"strconv"
+
+	"github.com/gocraft/dbr/v2/dialect"
 )
 
 // DeleteStmt builds `DELETE ...`.
@@ -19,10 +21,17 @@ type DeleteStmt struct {
 	LimitCount int64
 
 	comments Comments
+
+	shard interface{}
 }
 
 type DeleteBuilder = DeleteStmt
 
+// ToSQL returns the query with placeholders of dialect d, and its arguments.
+func (b *DeleteStmt) ToSQL(d Dialect) (string, []interface{}, error) {
+	return ToSQL(b, d)
+}
+
 func (b *DeleteStmt) Build(d Dialect, buf Buffer) error {
 	if b.raw.Query != "" {
 		return b.raw.Build(d, buf)
@@ -37,8 +46,18 @@ func (b *DeleteStmt) Build(d Dialect, buf Buffer) error {
 		return err
 	}
 
-	buf.WriteString("DELETE FROM ")
-	buf.WriteString(d.QuoteIdent(b.Table))
+	if mutationStyle(d) == dialect.AlterTable {
+		// rows are deleted with a mutation, like in clickhouse.
+		if b.LimitCount >= 0 {
+			return ErrNotSupported
+		}
+		buf.WriteString("ALTER TABLE ")
+		buf.WriteString(d.QuoteIdent(b.Table))
+		buf.WriteString(" DELETE")
+	} else {
+		buf.WriteString("DELETE FROM ")
+		buf.WriteString(d.QuoteIdent(b.Table))
+	}
 
 	if len(b.WhereCond) > 0 {
 		buf.WriteString(" WHERE ")
@@ -46,6 +65,10 @@ func (b *DeleteStmt) Build(d Dialect, buf Buffer) error {
 		if err != nil {
 			return err
 		}
+	} else if mutationStyle(d) == dialect.AlterTable {
+		// WHERE is required in a mutation.
+		buf.WriteString(" WHERE ")
+		buf.WriteString(encodeBoolCondition(d, true))
 	}
 	if b.LimitCount >= 0 {
 		buf.WriteString(" LIMIT ")
@@ -131,6 +154,13 @@ func (b *DeleteStmt) Comment(comment string) *DeleteStmt {
 	return b
 }
 
+// Shard sets the shard key of the statement, which is used instead of
+// the key in the context when it runs on a ShardedSession.
+func (b *DeleteStmt) Shard(key interface{}) *DeleteStmt {
+	b.shard = key
+	return b
+}
+
 func (b *DeleteStmt) Exec() (sql.Result, error) {
 	return b.ExecContext(context.Background())
 }
@@ -1,9 +1,18 @@
 package dbr
 
-import "time"
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
 
 // Dialect abstracts database driver differences in encoding
 // types, and placeholders.
+//
+// A Dialect can also implement LimitStyler, ReturningStyler, InsertStyler,
+// UpsertStyler, BulkUpdateStyler, MutationStyler, SavepointStyler,
+// ArrayStyler, BoolConditionEncoder, MaxParamser and MaxInLister
+// to change how statements are built.
 type Dialect interface {
 	QuoteIdent(id string) string
 
@@ -14,3 +23,143 @@ type Dialect interface {
 
 	Placeholder(n int) string
 }
+
+// LimitStyler is implemented by dialects that paginate
+// without `LIMIT n OFFSET m`.
+type LimitStyler interface {
+	LimitStyle() dialect.LimitStyle
+}
+
+// ReturningStyler is implemented by dialects that return columns
+// without `RETURNING col`.
+type ReturningStyler interface {
+	ReturningStyle() dialect.ReturningStyle
+}
+
+// InsertStyler is implemented by dialects that insert multiple rows
+// without `VALUES (a), (b)`.
+type InsertStyler interface {
+	InsertStyle() dialect.InsertStyle
+}
+
+// UpsertStyler is implemented by dialects that support upsert.
+type UpsertStyler interface {
+	UpsertStyle() dialect.UpsertStyle
+}
+
+// BulkUpdateStyler is implemented by dialects that support BulkUpdateStmt.
+type BulkUpdateStyler interface {
+	BulkUpdateStyle() dialect.BulkUpdateStyle
+}
+
+// MutationStyler is implemented by dialects that update or delete rows
+// without `UPDATE` and `DELETE` statements.
+type MutationStyler interface {
+	MutationStyle() dialect.MutationStyle
+}
+
+// SavepointStyler is implemented by dialects that use savepoints
+// without `SAVEPOINT name` and `RELEASE SAVEPOINT name`.
+type SavepointStyler interface {
+	SavepointStyle() dialect.SavepointStyle
+}
+
+// ArrayStyler is implemented by dialects that support array values.
+type ArrayStyler interface {
+	ArrayStyle() dialect.ArrayStyle
+}
+
+// BoolConditionEncoder is implemented by dialects that cannot use
+// EncodeBool as a condition, like `WHERE 0`.
+type BoolConditionEncoder interface {
+	EncodeBoolCondition(b bool) string
+}
+
+// MaxParamser is implemented by dialects that limit
+// the number of bind parameters in a statement.
+type MaxParamser interface {
+	MaxParams() int
+}
+
+// MaxInLister is implemented by dialects that limit
+// the number of values in `IN (...)`.
+type MaxInLister interface {
+	MaxInList() int
+}
+
+func limitStyle(d Dialect) dialect.LimitStyle {
+	if s, ok := d.(LimitStyler); ok {
+		return s.LimitStyle()
+	}
+	return dialect.LimitOffset
+}
+
+func returningStyle(d Dialect) dialect.ReturningStyle {
+	if s, ok := d.(ReturningStyler); ok {
+		return s.ReturningStyle()
+	}
+	return dialect.Returning
+}
+
+func insertStyle(d Dialect) dialect.InsertStyle {
+	if s, ok := d.(InsertStyler); ok {
+		return s.InsertStyle()
+	}
+	return dialect.InsertValues
+}
+
+func upsertStyle(d Dialect) dialect.UpsertStyle {
+	if s, ok := d.(UpsertStyler); ok {
+		return s.UpsertStyle()
+	}
+	return dialect.NoUpsert
+}
+
+func bulkUpdateStyle(d Dialect) dialect.BulkUpdateStyle {
+	if s, ok := d.(BulkUpdateStyler); ok {
+		return s.BulkUpdateStyle()
+	}
+	return dialect.NoBulkUpdate
+}
+
+func mutationStyle(d Dialect) dialect.MutationStyle {
+	if s, ok := d.(MutationStyler); ok {
+		return s.MutationStyle()
+	}
+	return dialect.MutationStatement
+}
+
+func savepointStyle(d Dialect) dialect.SavepointStyle {
+	if s, ok := d.(SavepointStyler); ok {
+		return s.SavepointStyle()
+	}
+	return dialect.Savepoint
+}
+
+func arrayStyle(d Dialect) dialect.ArrayStyle {
+	if s, ok := d.(ArrayStyler); ok {
+		return s.ArrayStyle()
+	}
+	return dialect.NoArray
+}
+
+func encodeBoolCondition(d Dialect, b bool) string {
+	if e, ok := d.(BoolConditionEncoder); ok {
+		return e.EncodeBoolCondition(b)
+	}
+	return d.EncodeBool(b)
+}
+
+func maxParams(d Dialect) int {
+	if m, ok := d.(MaxParamser); ok {
+		return m.MaxParams()
+	}
+	return 0
+}
+
+func maxInList(d Dialect) int {
+	if m, ok := d.(MaxInLister); ok {
+		return m.MaxInList()
+	}
+	return 0
+}
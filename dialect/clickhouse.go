@@ -0,0 +1,85 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type clickHouse struct{}
+
+func (d clickHouse) QuoteIdent(s string) string {
+	return quoteIdent(s, "`")
+}
+
+func (d clickHouse) EncodeString(s string) string {
+	var buf strings.Builder
+
+	buf.WriteRune('\'')
+	// https://clickhouse.com/docs/en/sql-reference/syntax#string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 0:
+			buf.WriteString(`\0`)
+		case '\'':
+			buf.WriteString(`\'`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+
+	buf.WriteRune('\'')
+	return buf.String()
+}
+
+func (d clickHouse) EncodeBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d clickHouse) EncodeTime(t time.Time) string {
+	return `toDateTime64('` + t.UTC().Format(timeFormat) + `', 6, 'UTC')`
+}
+
+func (d clickHouse) EncodeBytes(b []byte) string {
+	return fmt.Sprintf(`unhex('%x')`, b)
+}
+
+func (d clickHouse) Placeholder(_ int) string {
+	return "?"
+}
+
+func (d clickHouse) LimitStyle() LimitStyle {
+	return LimitOffset
+}
+
+func (d clickHouse) ReturningStyle() ReturningStyle {
+	return NoReturning
+}
+
+func (d clickHouse) MutationStyle() MutationStyle {
+	return AlterTable
+}
+
+func (d clickHouse) UpsertStyle() UpsertStyle {
+	return NoUpsert
+}
+
+func (d clickHouse) MaxParams() int {
+	// no limit
+	return 0
+}
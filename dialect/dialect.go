@@ -11,6 +11,10 @@ var (
 	SQLite3 = sqlite3{}
 	// MSSQL dialect
 	MSSQL = mssql{}
+	// Oracle dialect
+	Oracle = oracle{}
+	// ClickHouse dialect
+	ClickHouse = clickHouse{}
 )
 
 const (
@@ -24,3 +28,106 @@ func quoteIdent(s, quote string) string {
 	}
 	return quote + s + quote
 }
+
+// LimitStyle is the syntax that a dialect uses to paginate.
+type LimitStyle uint8
+
+const (
+	// LimitOffset is `LIMIT n OFFSET m`.
+	LimitOffset LimitStyle = iota
+	// OffsetFetch is `OFFSET m ROWS FETCH NEXT n ROWS ONLY`.
+	OffsetFetch
+	// OrderedOffsetFetch is OffsetFetch that requires ORDER BY.
+	OrderedOffsetFetch
+)
+
+// ReturningStyle is the syntax that a dialect uses to return
+// columns of inserted or updated rows.
+type ReturningStyle uint8
+
+const (
+	// Returning is `RETURNING col` at the end of the statement.
+	Returning ReturningStyle = iota
+	// ReturningInto is `RETURNING col INTO :n` with output parameters.
+	ReturningInto
+	// Output is `OUTPUT INSERTED.col` before VALUES or WHERE.
+	Output
+	// NoReturning means that returning columns is not supported.
+	NoReturning
+)
+
+// UpsertStyle is the syntax that a dialect uses to insert
+// or update rows with conflicting keys.
+type UpsertStyle uint8
+
+const (
+	// NoUpsert means that upsert is not supported.
+	NoUpsert UpsertStyle = iota
+	// OnConflict is `ON CONFLICT (key) DO UPDATE SET col = EXCLUDED.col`.
+	OnConflict
+	// OnDuplicateKey is `ON DUPLICATE KEY UPDATE col = VALUES(col)`.
+	OnDuplicateKey
+)
+
+// InsertStyle is the syntax that a dialect uses to insert multiple rows.
+type InsertStyle uint8
+
+const (
+	// InsertValues is `INSERT INTO t (col) VALUES (a), (b)`.
+	InsertValues InsertStyle = iota
+	// InsertAll is `INSERT ALL INTO t (col) VALUES (a) INTO t (col) VALUES (b) SELECT 1 FROM DUAL`.
+	InsertAll
+)
+
+// BulkUpdateStyle is the syntax that a dialect uses to update
+// many rows with their own values in one statement.
+type BulkUpdateStyle uint8
+
+const (
+	// NoBulkUpdate means that bulk update is not supported.
+	NoBulkUpdate BulkUpdateStyle = iota
+	// UpdateFromValues is `UPDATE t SET ... FROM (VALUES ...) AS v WHERE ...`.
+	UpdateFromValues
+	// UpdateJoinUnion is `UPDATE t JOIN (SELECT ... UNION ALL SELECT ...) AS v ON ... SET ...`.
+	UpdateJoinUnion
+	// UpdateJoinValues is `UPDATE t SET ... FROM t JOIN (VALUES ...) AS v ON ...`.
+	UpdateJoinValues
+	// UpdateCase is `UPDATE t SET col = CASE WHEN ... THEN ... END WHERE key IN (...)`.
+	UpdateCase
+)
+
+// MutationStyle is the syntax that a dialect uses to update or delete rows.
+type MutationStyle uint8
+
+const (
+	// MutationStatement is `UPDATE t SET col = a` and `DELETE FROM t`.
+	MutationStatement MutationStyle = iota
+	// AlterTable is `ALTER TABLE t UPDATE col = a WHERE cond`
+	// and `ALTER TABLE t DELETE WHERE cond`, which requires WHERE.
+	AlterTable
+)
+
+// SavepointStyle is the syntax that a dialect uses for savepoints.
+type SavepointStyle uint8
+
+const (
+	// Savepoint is `SAVEPOINT name`, `ROLLBACK TO SAVEPOINT name`
+	// and `RELEASE SAVEPOINT name`.
+	Savepoint SavepointStyle = iota
+	// SavepointNoRelease is Savepoint without `RELEASE SAVEPOINT`.
+	// Savepoints are released when the transaction ends.
+	SavepointNoRelease
+	// SaveTransaction is `SAVE TRANSACTION name` and `ROLLBACK TRANSACTION name`.
+	// Savepoints are released when the transaction ends.
+	SaveTransaction
+)
+
+// ArrayStyle is the syntax that a dialect uses for array values.
+type ArrayStyle uint8
+
+const (
+	// NoArray means that array values are not supported.
+	NoArray ArrayStyle = iota
+	// ArrayConstructor is `ARRAY[a,b,c]`.
+	ArrayConstructor
+)
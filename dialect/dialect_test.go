@@ -2,6 +2,7 @@ package dialect
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -77,3 +78,51 @@ func TestMSSQL(t *testing.T) {
 		require.Equal(t, test.want, MSSQL.QuoteIdent(test.in))
 	}
 }
+
+func TestOracle(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "table.col",
+			want: `"table"."col"`,
+		},
+		{
+			in:   "col",
+			want: `"col"`,
+		},
+	} {
+		require.Equal(t, test.want, Oracle.QuoteIdent(test.in))
+	}
+
+	require.Equal(t, `'a''b'`, Oracle.EncodeString("a'b"))
+	require.Equal(t, "1", Oracle.EncodeBool(true))
+	require.Equal(t, `TO_TIMESTAMP('2006-01-02 15:04:05.000000', 'YYYY-MM-DD HH24:MI:SS.FF6')`,
+		Oracle.EncodeTime(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)))
+	require.Equal(t, `HEXTORAW('0102ff')`, Oracle.EncodeBytes([]byte{1, 2, 255}))
+	require.Equal(t, ":2", Oracle.Placeholder(1))
+}
+
+func TestClickHouse(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "table.col",
+			want: "`table`.`col`",
+		},
+		{
+			in:   "col",
+			want: "`col`",
+		},
+	} {
+		require.Equal(t, test.want, ClickHouse.QuoteIdent(test.in))
+	}
+
+	require.Equal(t, `'a\'b\\c\n'`, ClickHouse.EncodeString("a'b\\c\n"))
+	require.Equal(t, `toDateTime64('2006-01-02 15:04:05.000000', 6, 'UTC')`,
+		ClickHouse.EncodeTime(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)))
+	require.Equal(t, `unhex('0102ff')`, ClickHouse.EncodeBytes([]byte{1, 2, 255}))
+}
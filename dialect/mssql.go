@@ -34,3 +34,35 @@ func (d mssql) EncodeBytes(b []byte) string {
 func (d mssql) Placeholder(n int) string {
 	return fmt.Sprintf("@p%d", n+1)
 }
+
+func (d mssql) LimitStyle() LimitStyle {
+	return OrderedOffsetFetch
+}
+
+func (d mssql) ReturningStyle() ReturningStyle {
+	return Output
+}
+
+func (d mssql) BulkUpdateStyle() BulkUpdateStyle {
+	return UpdateJoinValues
+}
+
+func (d mssql) UpsertStyle() UpsertStyle {
+	return NoUpsert
+}
+
+func (d mssql) SavepointStyle() SavepointStyle {
+	return SaveTransaction
+}
+
+func (d mssql) MaxParams() int {
+	return 2100
+}
+
+func (d mssql) EncodeBoolCondition(b bool) string {
+	// a number is not a condition
+	if b {
+		return "1 = 1"
+	}
+	return "1 = 0"
+}
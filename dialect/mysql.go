@@ -64,3 +64,24 @@ func (d mysql) EncodeBytes(b []byte) string {
 func (d mysql) Placeholder(_ int) string {
 	return "?"
 }
+
+func (d mysql) LimitStyle() LimitStyle {
+	return LimitOffset
+}
+
+func (d mysql) ReturningStyle() ReturningStyle {
+	return Returning
+}
+
+func (d mysql) BulkUpdateStyle() BulkUpdateStyle {
+	return UpdateJoinUnion
+}
+
+func (d mysql) UpsertStyle() UpsertStyle {
+	return OnDuplicateKey
+}
+
+func (d mysql) MaxParams() int {
+	// the number of placeholders in a prepared statement is uint16
+	return 65535
+}
@@ -0,0 +1,78 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type oracle struct{}
+
+func (d oracle) QuoteIdent(s string) string {
+	return quoteIdent(s, `"`)
+}
+
+func (d oracle) EncodeString(s string) string {
+	return `'` + strings.Replace(s, `'`, `''`, -1) + `'`
+}
+
+func (d oracle) EncodeBool(b bool) string {
+	// oracle has no boolean type in SQL before 23c
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d oracle) EncodeTime(t time.Time) string {
+	return `TO_TIMESTAMP('` + t.UTC().Format(timeFormat) + `', 'YYYY-MM-DD HH24:MI:SS.FF6')`
+}
+
+func (d oracle) EncodeBytes(b []byte) string {
+	return fmt.Sprintf(`HEXTORAW('%x')`, b)
+}
+
+func (d oracle) Placeholder(n int) string {
+	return fmt.Sprintf(":%d", n+1)
+}
+
+func (d oracle) LimitStyle() LimitStyle {
+	return OffsetFetch
+}
+
+func (d oracle) ReturningStyle() ReturningStyle {
+	return ReturningInto
+}
+
+func (d oracle) InsertStyle() InsertStyle {
+	return InsertAll
+}
+
+func (d oracle) BulkUpdateStyle() BulkUpdateStyle {
+	return UpdateCase
+}
+
+func (d oracle) UpsertStyle() UpsertStyle {
+	return NoUpsert
+}
+
+func (d oracle) SavepointStyle() SavepointStyle {
+	return SavepointNoRelease
+}
+
+func (d oracle) MaxParams() int {
+	return 65535
+}
+
+func (d oracle) MaxInList() int {
+	// ORA-01795
+	return 1000
+}
+
+func (d oracle) EncodeBoolCondition(b bool) string {
+	// a number is not a condition
+	if b {
+		return "1 = 1"
+	}
+	return "1 = 0"
+}
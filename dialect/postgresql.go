@@ -35,3 +35,28 @@ func (d postgreSQL) EncodeBytes(b []byte) string {
 func (d postgreSQL) Placeholder(n int) string {
 	return fmt.Sprintf("$%d", n+1)
 }
+
+func (d postgreSQL) LimitStyle() LimitStyle {
+	return LimitOffset
+}
+
+func (d postgreSQL) ReturningStyle() ReturningStyle {
+	return Returning
+}
+
+func (d postgreSQL) BulkUpdateStyle() BulkUpdateStyle {
+	return UpdateFromValues
+}
+
+func (d postgreSQL) UpsertStyle() UpsertStyle {
+	return OnConflict
+}
+
+func (d postgreSQL) ArrayStyle() ArrayStyle {
+	return ArrayConstructor
+}
+
+func (d postgreSQL) MaxParams() int {
+	// the number of parameters in the extended protocol is uint16
+	return 65535
+}
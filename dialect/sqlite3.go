@@ -38,3 +38,24 @@ func (d sqlite3) EncodeBytes(b []byte) string {
 func (d sqlite3) Placeholder(_ int) string {
 	return "?"
 }
+
+func (d sqlite3) LimitStyle() LimitStyle {
+	return LimitOffset
+}
+
+func (d sqlite3) ReturningStyle() ReturningStyle {
+	return Returning
+}
+
+func (d sqlite3) BulkUpdateStyle() BulkUpdateStyle {
+	return UpdateCase
+}
+
+func (d sqlite3) UpsertStyle() UpsertStyle {
+	return OnConflict
+}
+
+func (d sqlite3) MaxParams() int {
+	// SQLITE_MAX_VARIABLE_NUMBER
+	return 999
+}
@@ -0,0 +1,124 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+// testDialect is postgres that paginates with OFFSET / FETCH,
+// and returns columns with OUTPUT.
+type testDialect struct {
+	Dialect
+}
+
+func (d testDialect) LimitStyle() dialect.LimitStyle {
+	return dialect.OffsetFetch
+}
+
+func (d testDialect) ReturningStyle() dialect.ReturningStyle {
+	return dialect.Output
+}
+
+func (d testDialect) UpsertStyle() dialect.UpsertStyle {
+	return dialect.OnDuplicateKey
+}
+
+func (d testDialect) EncodeBoolCondition(b bool) string {
+	if b {
+		return "TRUE = TRUE"
+	}
+	return "TRUE = FALSE"
+}
+
+func (d testDialect) MaxParams() int {
+	return 10
+}
+
+func TestDialectCapability(t *testing.T) {
+	d := testDialect{Dialect: dialect.PostgreSQL}
+
+	for _, test := range []struct {
+		builder Builder
+		want    string
+	}{
+		{
+			builder: Select("a").From("t").Where(Eq("b", []int{})).Limit(1).Offset(2),
+			want:    `SELECT a FROM t WHERE (TRUE = FALSE) OFFSET 2 ROWS FETCH NEXT 1 ROWS ONLY`,
+		},
+		{
+			builder: InsertInto("t").Columns("a").Values(1).OnConflict("a").DoUpdate("a").Returning("id"),
+			want:    `INSERT INTO "t" ("a") OUTPUT INSERTED."id" VALUES (1) ON DUPLICATE KEY UPDATE "a" = VALUES("a")`,
+		},
+		{
+			builder: Update("t").Set("a", 1).Where(Neq("b", []int{})).Returning("id"),
+			want:    `UPDATE "t" SET "a" = 1 OUTPUT INSERTED."id" WHERE (TRUE = TRUE)`,
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.builder}, d)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+	}
+	require.Equal(t, BatchLimit{MaxParams: 10}, defaultBatchLimit(d))
+
+	// a dialect without capabilities uses the defaults
+	s, err := InterpolateForDialect("?", []interface{}{
+		Select("a").From("t").Where(Eq("b", []int{})).Limit(1),
+	}, struct{ Dialect }{dialect.MSSQL})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT a FROM t WHERE (0) LIMIT 1`, s)
+}
+
+func TestUpsert(t *testing.T) {
+	for _, test := range []struct {
+		d       Dialect
+		builder *InsertStmt
+		want    string
+	}{
+		{
+			d:       dialect.PostgreSQL,
+			builder: InsertInto("t").Columns("id", "a", "b").Values(1, 2, 3).OnConflict("id").DoUpdate("a", "b").Returning("id"),
+			want:    `INSERT INTO "t" ("id","a","b") VALUES (1,2,3) ON CONFLICT ("id") DO UPDATE SET "a" = EXCLUDED."a", "b" = EXCLUDED."b" RETURNING "id"`,
+		},
+		{
+			d:       dialect.SQLite3,
+			builder: InsertInto("t").Columns("id", "a").Values(1, 2).OnConflict("id"),
+			want:    `INSERT INTO "t" ("id","a") VALUES (1,2) ON CONFLICT ("id") DO NOTHING`,
+		},
+		{
+			d:       dialect.MySQL,
+			builder: InsertInto("t").Columns("id", "a").Values(1, 2).OnConflict().DoUpdate("a"),
+			want:    "INSERT INTO `t` (`id`,`a`) VALUES (1,2) ON DUPLICATE KEY UPDATE `a` = VALUES(`a`)",
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.builder}, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+	}
+
+	for _, test := range []struct {
+		d       Dialect
+		builder *InsertStmt
+	}{
+		{
+			d:       dialect.MySQL,
+			builder: InsertInto("t").Columns("id").Values(1).OnConflict("id"),
+		},
+		{
+			d:       dialect.MSSQL,
+			builder: InsertInto("t").Columns("id").Values(1).OnConflict("id").DoUpdate("id"),
+		},
+	} {
+		_, err := InterpolateForDialect("?", []interface{}{test.builder}, test.d)
+		require.Equal(t, ErrNotSupported, err)
+	}
+}
+
+func TestMSSQLBoolCondition(t *testing.T) {
+	s, err := InterpolateForDialect("?", []interface{}{
+		Select("a").From("t").Where(Eq("b", []int{})).Where(Neq("c", []int{})),
+	}, dialect.MSSQL)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT a FROM t WHERE (1 = 0) AND (1 = 1)`, s)
+}
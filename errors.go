@@ -5,12 +5,37 @@ import "errors"
 // package errors
 var (
 	ErrNotFound           = errors.New("dbr: not found")
+	ErrMultipleRows       = errors.New("dbr: more than one row")
 	ErrNotSupported       = errors.New("dbr: not supported")
 	ErrTableNotSpecified  = errors.New("dbr: table not specified")
 	ErrColumnNotSpecified = errors.New("dbr: column not specified")
+	ErrValueNotSpecified  = errors.New("dbr: value not specified")
 	ErrInvalidPointer     = errors.New("dbr: attempt to load into an invalid pointer")
 	ErrPlaceholderCount   = errors.New("dbr: wrong placeholder count")
 	ErrInvalidSliceLength = errors.New("dbr: length of slice is 0. length must be >= 1")
 	ErrCantConvertToTime  = errors.New("dbr: can't convert to time.Time")
 	ErrInvalidTimestring  = errors.New("dbr: invalid time string")
+	ErrInvalidArray       = errors.New("dbr: invalid array text")
+	ErrTemplateParam      = errors.New("dbr: template parameter used outside of Compile")
+	ErrTemplateArg        = errors.New("dbr: template argument not specified")
+	ErrShardNotFound      = errors.New("dbr: shard not found")
+	ErrNoShardKey         = errors.New("dbr: shard key not specified")
+	ErrInvalidRelation    = errors.New("dbr: invalid relation")
+	ErrInvalidSavepoint   = errors.New("dbr: invalid savepoint name")
+	ErrInvalidSetting     = errors.New("dbr: invalid setting name")
+	ErrNestedTxOpen       = errors.New("dbr: nested transaction is still open")
+	ErrFieldNotFound      = errors.New("dbr: no field for column")
+)
+
+// database errors, which are matched by DBError
+var (
+	ErrUniqueViolation      = errors.New("dbr: unique violation")
+	ErrForeignKeyViolation  = errors.New("dbr: foreign key violation")
+	ErrNotNullViolation     = errors.New("dbr: not-null violation")
+	ErrCheckViolation       = errors.New("dbr: check violation")
+	ErrDeadlock             = errors.New("dbr: deadlock")
+	ErrSerializationFailure = errors.New("dbr: serialization failure")
+	ErrLockTimeout          = errors.New("dbr: lock timeout")
+	ErrConnectionLost       = errors.New("dbr: connection lost")
+	ErrQueryCanceled        = errors.New("dbr: query canceled")
 )
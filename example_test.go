@@ -1,9 +1,12 @@
 package dbr
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"time"
+
+	"github.com/gocraft/dbr/v2/dialect"
 )
 
 func ExampleOpen() {
@@ -184,3 +187,17 @@ func ExampleIterator() {
 		log.Fatalf("iter close err %v", err)
 	}
 }
+
+func ExampleRegisterDialect() {
+	// use the dialect of postgres for connections opened with a wrapper driver
+	RegisterDialect("cloudsqlpostgres", dialect.PostgreSQL)
+	conn, _ := Open("cloudsqlpostgres", "...", nil)
+	conn.NewSession(nil)
+}
+
+func ExampleNewConnection() {
+	// wrap *sql.DB that is opened somewhere else
+	db, _ := sql.Open("sqlserver", "...")
+	conn := NewConnection(db, dialect.MSSQL, nil)
+	conn.NewSession(nil)
+}
@@ -0,0 +1,255 @@
+package dbr
+
+import (
+	"strings"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// SearchMode controls how a full-text query string is parsed.
+type SearchMode uint8
+
+// full-text search modes
+const (
+	// NaturalLanguageMode treats the query as plain words.
+	NaturalLanguageMode SearchMode = iota
+	// BooleanMode passes the query with the operators of the database,
+	// like `+go -java` in MySQL or `go & !java` in PostgreSQL.
+	BooleanMode
+)
+
+// FullTextSearch builds a full-text search condition in the native syntax
+// of each dialect:
+//
+//	MySQL:      MATCH (`title`,`body`) AGAINST ('query' IN NATURAL LANGUAGE MODE)
+//	PostgreSQL: to_tsvector('english', ...) @@ plainto_tsquery('english', 'query')
+//	SQLite3:    "title" MATCH 'query' (FTS5)
+//	MSSQL:      FREETEXT(("title","body"), 'query')
+type FullTextSearch struct {
+	Column         []string
+	Query          string
+	Mode           SearchMode
+	LanguageConfig string
+}
+
+// FullText creates a FullTextSearch for query over columns.
+//
+// In SQLite3, column may be the FTS5 table name to search all of its columns.
+func FullText(query string, column ...string) *FullTextSearch {
+	return &FullTextSearch{
+		Column: column,
+		Query:  query,
+	}
+}
+
+// BooleanMode uses the boolean query syntax of the database.
+func (s *FullTextSearch) BooleanMode() *FullTextSearch {
+	s.Mode = BooleanMode
+	return s
+}
+
+// NaturalLanguageMode treats the query as plain words. This is the default.
+func (s *FullTextSearch) NaturalLanguageMode() *FullTextSearch {
+	s.Mode = NaturalLanguageMode
+	return s
+}
+
+// Language sets the text search configuration in PostgreSQL (like `english`),
+// or the language term in MSSQL. It is ignored by MySQL and SQLite3,
+// where it is chosen when the index is created.
+func (s *FullTextSearch) Language(config string) *FullTextSearch {
+	s.LanguageConfig = config
+	return s
+}
+
+// Build writes the full-text condition.
+func (s *FullTextSearch) Build(d Dialect, buf Buffer) error {
+	switch d {
+	case dialect.MySQL:
+		return s.buildMySQL(d, buf)
+	case dialect.PostgreSQL:
+		if len(s.Column) == 0 {
+			return ErrColumnNotSpecified
+		}
+		s.buildTSVector(d, buf)
+		buf.WriteString(" @@ ")
+		s.buildTSQuery(buf)
+		return nil
+	case dialect.SQLite3:
+		if len(s.Column) == 0 {
+			return ErrColumnNotSpecified
+		}
+		query := s.Query
+		if s.Mode == NaturalLanguageMode {
+			query = fts5Terms(query)
+		}
+		if len(s.Column) > 1 {
+			buf.WriteString("(")
+		}
+		for i, col := range s.Column {
+			if i > 0 {
+				buf.WriteString(" OR ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(" MATCH ")
+			buf.WriteString(placeholder)
+			buf.WriteValue(query)
+		}
+		if len(s.Column) > 1 {
+			buf.WriteString(")")
+		}
+		return nil
+	case dialect.MSSQL:
+		if s.Mode == BooleanMode {
+			buf.WriteString("CONTAINS(")
+		} else {
+			buf.WriteString("FREETEXT(")
+		}
+		switch len(s.Column) {
+		case 0:
+			buf.WriteString("*")
+		case 1:
+			buf.WriteString(d.QuoteIdent(s.Column[0]))
+		default:
+			buf.WriteString("(")
+			s.buildColumnList(d, buf)
+			buf.WriteString(")")
+		}
+		buf.WriteString(", ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(s.Query)
+		if s.LanguageConfig != "" {
+			buf.WriteString(", LANGUAGE ")
+			buf.WriteString(placeholder)
+			buf.WriteValue(s.LanguageConfig)
+		}
+		buf.WriteString(")")
+		return nil
+	}
+	return ErrNotSupported
+}
+
+// Score returns the relevance of each row to the search, where higher is more relevant.
+// Select it with an alias like `search.Score().As("score")`, then order by that alias.
+//
+// In SQLite3, the score is `-rank` of the FTS5 table in the query,
+// so the columns of the search are ignored.
+// MSSQL only exposes relevance through CONTAINSTABLE, so it is not supported there.
+func (s *FullTextSearch) Score() FullTextScore {
+	return FullTextScore{Search: s}
+}
+
+// FullTextScore is the relevance of a FullTextSearch, created by Score.
+type FullTextScore struct {
+	Search *FullTextSearch
+}
+
+// Build writes the relevance expression.
+func (score FullTextScore) Build(d Dialect, buf Buffer) error {
+	s := score.Search
+	switch d {
+	case dialect.MySQL:
+		return s.buildMySQL(d, buf)
+	case dialect.PostgreSQL:
+		if len(s.Column) == 0 {
+			return ErrColumnNotSpecified
+		}
+		buf.WriteString("ts_rank(")
+		s.buildTSVector(d, buf)
+		buf.WriteString(", ")
+		s.buildTSQuery(buf)
+		buf.WriteString(")")
+		return nil
+	case dialect.SQLite3:
+		// FTS5 rank is bm25, where more negative is more relevant.
+		buf.WriteString("-rank")
+		return nil
+	}
+	return ErrNotSupported
+}
+
+// As adds an alias to the score.
+func (score FullTextScore) As(alias string) Builder {
+	return as(score, alias)
+}
+
+func (s *FullTextSearch) buildColumnList(d Dialect, buf Buffer) {
+	for i, col := range s.Column {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+}
+
+// https://dev.mysql.com/doc/refman/8.0/en/fulltext-search.html
+func (s *FullTextSearch) buildMySQL(d Dialect, buf Buffer) error {
+	if len(s.Column) == 0 {
+		return ErrColumnNotSpecified
+	}
+	buf.WriteString("MATCH (")
+	s.buildColumnList(d, buf)
+	buf.WriteString(") AGAINST (")
+	buf.WriteString(placeholder)
+	buf.WriteValue(s.Query)
+	if s.Mode == BooleanMode {
+		buf.WriteString(" IN BOOLEAN MODE)")
+	} else {
+		buf.WriteString(" IN NATURAL LANGUAGE MODE)")
+	}
+	return nil
+}
+
+// https://www.postgresql.org/docs/current/textsearch-controls.html
+func (s *FullTextSearch) buildTSVector(d Dialect, buf Buffer) {
+	buf.WriteString("to_tsvector(")
+	if s.LanguageConfig != "" {
+		buf.WriteString(placeholder)
+		buf.WriteValue(s.LanguageConfig)
+		buf.WriteString(", ")
+	}
+	if len(s.Column) == 1 {
+		buf.WriteString(d.QuoteIdent(s.Column[0]))
+	} else {
+		for i, col := range s.Column {
+			if i > 0 {
+				buf.WriteString(" || ' ' || ")
+			}
+			buf.WriteString("coalesce(")
+			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(", '')")
+		}
+	}
+	buf.WriteString(")")
+}
+
+func (s *FullTextSearch) buildTSQuery(buf Buffer) {
+	if s.Mode == BooleanMode {
+		buf.WriteString("to_tsquery(")
+	} else {
+		buf.WriteString("plainto_tsquery(")
+	}
+	if s.LanguageConfig != "" {
+		buf.WriteString(placeholder)
+		buf.WriteValue(s.LanguageConfig)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(placeholder)
+	buf.WriteValue(s.Query)
+	buf.WriteString(")")
+}
+
+// fts5Terms quotes each word so that FTS5 operators in the query are
+// matched literally. https://www.sqlite.org/fts5.html#full_text_query_syntax
+func fts5Terms(query string) string {
+	var buf strings.Builder
+	for i, word := range strings.Fields(query) {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+		buf.WriteString(`"`)
+		buf.WriteString(strings.Replace(word, `"`, `""`, -1))
+		buf.WriteString(`"`)
+	}
+	return buf.String()
+}
@@ -0,0 +1,95 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullText(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		cond  Builder
+		query string
+	}{
+		{
+			d:     dialect.MySQL,
+			cond:  FullText("go sql", "title", "body"),
+			query: "MATCH (`title`,`body`) AGAINST ('go sql' IN NATURAL LANGUAGE MODE)",
+		},
+		{
+			d:     dialect.MySQL,
+			cond:  FullText("+go -java", "title").BooleanMode(),
+			query: "MATCH (`title`) AGAINST ('+go -java' IN BOOLEAN MODE)",
+		},
+		{
+			d:     dialect.MySQL,
+			cond:  FullText("go", "title").Score().As("score"),
+			query: "MATCH (`title`) AGAINST ('go' IN NATURAL LANGUAGE MODE) AS `score`",
+		},
+		{
+			d:     dialect.PostgreSQL,
+			cond:  FullText("go sql", "title").Language("english"),
+			query: `to_tsvector('english', "title") @@ plainto_tsquery('english', 'go sql')`,
+		},
+		{
+			d:     dialect.PostgreSQL,
+			cond:  FullText("go & !java", "title", "body").BooleanMode(),
+			query: `to_tsvector(coalesce("title", '') || ' ' || coalesce("body", '')) @@ to_tsquery('go & !java')`,
+		},
+		{
+			d:     dialect.PostgreSQL,
+			cond:  FullText("go", "title").Score(),
+			query: `ts_rank(to_tsvector("title"), plainto_tsquery('go'))`,
+		},
+		{
+			d:     dialect.SQLite3,
+			cond:  FullText(`go "sql`, "posts"),
+			query: `"posts" MATCH '"go" """sql"'`,
+		},
+		{
+			d:     dialect.SQLite3,
+			cond:  FullText("go OR sql", "title", "body").BooleanMode(),
+			query: `("title" MATCH 'go OR sql' OR "body" MATCH 'go OR sql')`,
+		},
+		{
+			d:     dialect.SQLite3,
+			cond:  FullText("go", "posts").Score().As("score"),
+			query: `-rank AS "score"`,
+		},
+		{
+			d:     dialect.MSSQL,
+			cond:  FullText("go sql", "title", "body"),
+			query: `FREETEXT(("title","body"), 'go sql')`,
+		},
+		{
+			d:     dialect.MSSQL,
+			cond:  FullText(`"go" AND "sql"`).BooleanMode().Language("English"),
+			query: `CONTAINS(*, '"go" AND "sql"', LANGUAGE 'English')`,
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.cond}, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.query, s)
+	}
+
+	_, err := InterpolateForDialect("?", []interface{}{FullText("go", "title").Score()}, dialect.MSSQL)
+	require.Equal(t, ErrNotSupported, err)
+
+	_, err = InterpolateForDialect("?", []interface{}{FullText("go")}, dialect.MySQL)
+	require.Equal(t, ErrColumnNotSpecified, err)
+}
+
+func TestFullTextSelect(t *testing.T) {
+	search := FullText("go", "title").BooleanMode()
+	s, err := InterpolateForDialect("?", []interface{}{
+		Select("id", search.Score().As("score")).
+			From("posts").
+			Where(search).
+			OrderBy("score DESC"),
+	}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id, MATCH (`title`) AGAINST ('go' IN BOOLEAN MODE) AS `score` FROM posts "+
+		"WHERE (MATCH (`title`) AGAINST ('go' IN BOOLEAN MODE)) ORDER BY score DESC", s)
+}
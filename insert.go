@@ -22,13 +22,28 @@ type InsertStmt struct {
 	Value        [][]interface{}
 	Ignored      bool
 	ReturnColumn []string
+	ReturnDest   []interface{}
 	RecordID     *int64
+	BatchLimit   *BatchLimit
 	comments     Comments
+	upsert       *upsert
+	shard        interface{}
+	// err is returned by Build, if records cannot be added.
+	err error
 }
 
 type InsertBuilder = InsertStmt
 
+// ToSQL returns the query with placeholders of dialect d, and its arguments.
+func (b *InsertStmt) ToSQL(d Dialect) (string, []interface{}, error) {
+	return ToSQL(b, d)
+}
+
 func (b *InsertStmt) Build(d Dialect, buf Buffer) error {
+	if b.err != nil {
+		return b.err
+	}
+
 	if b.raw.Query != "" {
 		return b.raw.Build(d, buf)
 	}
@@ -46,6 +61,10 @@ func (b *InsertStmt) Build(d Dialect, buf Buffer) error {
 		return err
 	}
 
+	if len(b.Value) > 1 && insertStyle(d) == dialect.InsertAll {
+		return b.buildInsertAll(d, buf)
+	}
+
 	if b.Ignored {
 		buf.WriteString("INSERT IGNORE INTO ")
 	} else {
@@ -67,14 +86,8 @@ func (b *InsertStmt) Build(d Dialect, buf Buffer) error {
 	}
 	buf.WriteString(")")
 
-	if d == dialect.MSSQL && len(b.ReturnColumn) > 0 {
-		buf.WriteString(" OUTPUT ")
-		for i, col := range b.ReturnColumn {
-			if i > 0 {
-				buf.WriteString(",")
-			}
-			buf.WriteString("INSERTED." + d.QuoteIdent(col))
-		}
+	if returningStyle(d) == dialect.Output {
+		buildOutput(d, buf, b.ReturnColumn)
 	}
 
 	buf.WriteString(" VALUES ")
@@ -90,16 +103,102 @@ func (b *InsertStmt) Build(d Dialect, buf Buffer) error {
 		buf.WriteValue(tuple...)
 	}
 
-	if d != dialect.MSSQL && len(b.ReturnColumn) > 0 {
-		buf.WriteString(" RETURNING ")
-		for i, col := range b.ReturnColumn {
-			if i > 0 {
-				buf.WriteString(",")
-			}
-			buf.WriteString(d.QuoteIdent(col))
+	if b.upsert != nil {
+		err := b.upsert.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if returningStyle(d) != dialect.Output {
+		err := buildReturning(d, buf, b.ReturnColumn, b.ReturnDest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildInsertAll writes each row as `INTO t (col) VALUES (a)` of `INSERT ALL`.
+func (b *InsertStmt) buildInsertAll(d Dialect, buf Buffer) error {
+	if b.Ignored || b.upsert != nil || len(b.ReturnColumn) > 0 {
+		return ErrNotSupported
+	}
+
+	var into strings.Builder
+	into.WriteString(" INTO ")
+	into.WriteString(d.QuoteIdent(b.Table))
+	into.WriteString(" (")
+	for i, col := range b.Column {
+		if i > 0 {
+			into.WriteString(",")
+		}
+		into.WriteString(d.QuoteIdent(col))
+	}
+	into.WriteString(") VALUES (")
+	for i := range b.Column {
+		if i > 0 {
+			into.WriteString(",")
 		}
+		into.WriteString(placeholder)
 	}
+	into.WriteString(")")
+	intoStr := into.String()
 
+	buf.WriteString("INSERT ALL")
+	for _, tuple := range b.Value {
+		buf.WriteString(intoStr)
+		buf.WriteValue(tuple...)
+	}
+	buf.WriteString(" SELECT 1 FROM DUAL")
+	return nil
+}
+
+// buildOutput writes `OUTPUT` with column.
+func buildOutput(d Dialect, buf Buffer, column []string) {
+	if len(column) == 0 {
+		return
+	}
+	buf.WriteString(" OUTPUT ")
+	for i, col := range column {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("INSERTED." + d.QuoteIdent(col))
+	}
+}
+
+// buildReturning writes `RETURNING` with column. Some dialects like oracle
+// only return values into output bind parameters, so dest is written as `INTO`.
+func buildReturning(d Dialect, buf Buffer, column []string, dest []interface{}) error {
+	if len(column) == 0 {
+		return nil
+	}
+	if returningStyle(d) == dialect.NoReturning {
+		return ErrNotSupported
+	}
+	buf.WriteString(" RETURNING ")
+	for i, col := range column {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	if returningStyle(d) != dialect.ReturningInto {
+		return nil
+	}
+	if len(dest) != len(column) {
+		return ErrPlaceholderCount
+	}
+	buf.WriteString(" INTO ")
+	for i, v := range dest {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(placeholder)
+		buf.WriteValue(sql.Out{Dest: v})
+	}
 	return nil
 }
 
@@ -167,6 +266,13 @@ func (b *InsertStmt) Comment(comment string) *InsertStmt {
 	return b
 }
 
+// Shard sets the shard key of the statement, which is used instead of
+// the key in the context when it runs on a ShardedSession.
+func (b *InsertStmt) Shard(key interface{}) *InsertStmt {
+	b.shard = key
+	return b
+}
+
 // Ignore any insertion errors
 func (b *InsertStmt) Ignore() *InsertStmt {
 	b.Ignored = true
@@ -219,6 +325,88 @@ func (b *InsertStmt) Returning(column ...string) *InsertStmt {
 	return b
 }
 
+// ReturningInto adds a returning column that is loaded into dest
+// with `RETURNING ... INTO` for oracle.
+func (b *InsertStmt) ReturningInto(column string, dest interface{}) *InsertStmt {
+	b.ReturnColumn = append(b.ReturnColumn, column)
+	b.ReturnDest = append(b.ReturnDest, dest)
+	return b
+}
+
+// OnConflict turns the statement into an upsert on the unique key columns.
+// Rows that conflict with an existing row are skipped, unless DoUpdate is used.
+// Mysql ignores key, and uses any unique index.
+func (b *InsertStmt) OnConflict(key ...string) *InsertStmt {
+	if b.upsert == nil {
+		b.upsert = &upsert{}
+	}
+	b.upsert.key = key
+	return b
+}
+
+// DoUpdate updates column of an existing row with the value
+// that conflicts with it when OnConflict is used.
+func (b *InsertStmt) DoUpdate(column ...string) *InsertStmt {
+	if b.upsert == nil {
+		b.upsert = &upsert{}
+	}
+	b.upsert.column = append(b.upsert.column, column...)
+	return b
+}
+
+type upsert struct {
+	key    []string
+	column []string
+}
+
+func (u *upsert) Build(d Dialect, buf Buffer) error {
+	switch upsertStyle(d) {
+	case dialect.OnConflict:
+		buf.WriteString(" ON CONFLICT ")
+		if len(u.key) > 0 {
+			buf.WriteString("(")
+			for i, col := range u.key {
+				if i > 0 {
+					buf.WriteString(",")
+				}
+				buf.WriteString(d.QuoteIdent(col))
+			}
+			buf.WriteString(") ")
+		}
+		if len(u.column) == 0 {
+			buf.WriteString("DO NOTHING")
+			return nil
+		}
+		buf.WriteString("DO UPDATE SET ")
+		for i, col := range u.column {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(" = EXCLUDED.")
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		return nil
+	case dialect.OnDuplicateKey:
+		if len(u.column) == 0 {
+			// use Ignore instead
+			return ErrNotSupported
+		}
+		buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+		for i, col := range u.column {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(" = VALUES(")
+			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(")")
+		}
+		return nil
+	}
+	return ErrNotSupported
+}
+
 // Pair adds (column, value) to be inserted.
 // It is an error to mix Pair with Values and Record.
 func (b *InsertStmt) Pair(column string, value interface{}) *InsertStmt {
@@ -0,0 +1,204 @@
+package dbr
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// BatchLimit bounds each statement that ExecBatch sends.
+// A zero field means no limit.
+type BatchLimit struct {
+	// MaxRows is the number of rows in one VALUES list.
+	MaxRows int
+	// MaxParams is the number of bind parameters in one statement.
+	MaxParams int
+	// MaxBytes is the length of the SQL text of one statement.
+	MaxBytes int
+}
+
+// defaultBatchLimit returns the limits of a dialect with default server settings.
+func defaultBatchLimit(d Dialect) BatchLimit {
+	limit := BatchLimit{MaxParams: maxParams(d)}
+	switch d {
+	case dialect.MySQL:
+		// max_allowed_packet is 4MB by default before MySQL 8.0.
+		limit.MaxBytes = 4 << 20
+	case dialect.SQLite3:
+		// SQLITE_MAX_SQL_LENGTH
+		limit.MaxBytes = 1000000
+	case dialect.MSSQL:
+		limit.MaxRows = 1000
+	}
+	return limit
+}
+
+// BatchResult is the result of a statement that is split into batches.
+type BatchResult struct {
+	// RowsAffected is the total number of rows affected.
+	RowsAffected int64
+	// ID holds the first returning column of each row if Returning is used.
+	ID []int64
+	// Batches is the number of statements executed.
+	Batches int
+}
+
+// Records adds a tuple for each element of slice.
+// Elements can be structs, like Record, or slices of values, like Values.
+// Struct elements must be pointers or addressable, like the elements of a slice.
+//
+// If an element cannot be added, the statement fails to build
+// with ErrInvalidPointer, or ErrColumnNotSpecified if Columns is not called
+// before Records of structs.
+func (b *InsertStmt) Records(slice interface{}) *InsertStmt {
+	v := reflect.Indirect(reflect.ValueOf(slice))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		b.err = ErrInvalidPointer
+		return b
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		switch {
+		case elem.Kind() == reflect.Ptr && !elem.IsNil() && elem.Elem().Kind() == reflect.Struct,
+			elem.Kind() == reflect.Struct:
+			if len(b.Column) == 0 {
+				b.err = ErrColumnNotSpecified
+				return b
+			}
+			if elem.Kind() == reflect.Struct {
+				if !elem.CanAddr() {
+					// the id field cannot be set
+					b.err = ErrInvalidPointer
+					return b
+				}
+				elem = elem.Addr()
+			}
+			b.Record(elem.Interface())
+		case elem.Kind() == reflect.Slice:
+			tuple := make([]interface{}, elem.Len())
+			for j := range tuple {
+				tuple[j] = elem.Index(j).Interface()
+			}
+			b.Values(tuple...)
+		default:
+			b.err = ErrInvalidPointer
+			return b
+		}
+	}
+	// RecordID only makes sense for a single row.
+	b.RecordID = nil
+	return b
+}
+
+// Batch overrides the limits that ExecBatch uses to split rows.
+func (b *InsertStmt) Batch(limit BatchLimit) *InsertStmt {
+	b.BatchLimit = &limit
+	return b
+}
+
+// ExecBatch inserts rows in as many statements as needed to stay within
+// the limits of the dialect, like MySQL's max_allowed_packet or
+// the 2100 parameters of MSSQL.
+//
+// Statements run one after another on the runner of InsertStmt, so when
+// it is created from a Tx, all of them are in that transaction.
+func (b *InsertStmt) ExecBatch() (BatchResult, error) {
+	return b.ExecBatchContext(context.Background())
+}
+
+// ExecBatchContext is like ExecBatch with a context.
+func (b *InsertStmt) ExecBatchContext(ctx context.Context) (BatchResult, error) {
+	var res BatchResult
+	if b.err != nil {
+		return res, b.err
+	}
+	bind := usePreparedStmt(b.Runner) || useBindParams(b.Runner)
+	batches, err := b.batches(b.Dialect, bind)
+	if err != nil {
+		return res, err
+	}
+	for _, value := range batches {
+		stmt := *b
+		stmt.Value = value
+		stmt.RecordID = nil
+
+		if len(b.ReturnColumn) > 0 {
+			n, err := query(ctx, stmt.Runner, stmt.EventReceiver, &stmt, stmt.Dialect, &res.ID)
+			if err != nil {
+				return res, err
+			}
+			res.RowsAffected += int64(n)
+		} else {
+			result, err := exec(ctx, stmt.Runner, stmt.EventReceiver, &stmt, stmt.Dialect)
+			if err != nil {
+				return res, err
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return res, err
+			}
+			res.RowsAffected += n
+		}
+		res.Batches++
+	}
+	return res, nil
+}
+
+// batches splits Value so that each statement stays within BatchLimit.
+func (b *InsertStmt) batches(d Dialect, bind bool) ([][][]interface{}, error) {
+	if b.raw.Query != "" || len(b.Value) == 0 {
+		return [][][]interface{}{b.Value}, nil
+	}
+	limit := defaultBatchLimit(d)
+	if b.BatchLimit != nil {
+		limit = *b.BatchLimit
+	}
+
+	head := *b
+	head.Value = nil
+	buf := NewBuffer()
+	err := head.Build(d, buf)
+	if err != nil {
+		return nil, err
+	}
+	return splitBatch(d, limit, len(buf.String()), b.Value, 1, bind)
+}
+
+// splitBatch splits rows into batches that stay within limit,
+// where each statement has headSize bytes in addition to the rows,
+// and each row appears repeat times in the statement.
+// If bind is true, every value is counted as a bind parameter.
+func splitBatch(d Dialect, limit BatchLimit, headSize int, value [][]interface{}, repeat int, bind bool) ([][][]interface{}, error) {
+	var batches [][][]interface{}
+	start, size, params := 0, headSize, 0
+	for n, tuple := range value {
+		i := interpolator{
+			Buffer:       NewBuffer(),
+			Dialect:      d,
+			IgnoreBinary: true,
+			Bind:         bind,
+			N:            params,
+		}
+		err := i.interpolate(strings.TrimPrefix(strings.Repeat(","+placeholder, len(tuple)), ","), tuple, true)
+		if err != nil {
+			return nil, err
+		}
+		rowSize := (len(i.String()) + len("(), ")) * repeat
+		rowParams := len(i.Value()) * repeat
+
+		if n > start && (limit.MaxRows > 0 && n-start+1 > limit.MaxRows ||
+			limit.MaxParams > 0 && params+rowParams > limit.MaxParams ||
+			limit.MaxBytes > 0 && size+rowSize > limit.MaxBytes) {
+			batches = append(batches, value[start:n])
+			start, size, params = n, headSize, 0
+		}
+		size += rowSize
+		params += rowParams
+	}
+	return append(batches, value[start:]), nil
+}
@@ -0,0 +1,168 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertBatches(t *testing.T) {
+	rows := func(n int, value interface{}) [][]interface{} {
+		var v [][]interface{}
+		for i := 0; i < n; i++ {
+			v = append(v, []interface{}{i, value})
+		}
+		return v
+	}
+	sizes := func(batches [][][]interface{}) []int {
+		var n []int
+		for _, batch := range batches {
+			n = append(n, len(batch))
+		}
+		return n
+	}
+
+	for _, test := range []struct {
+		d     Dialect
+		stmt  *InsertStmt
+		bind  bool
+		sizes []int
+	}{
+		{
+			d:     dialect.MSSQL,
+			stmt:  &InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(2500, "x")},
+			sizes: []int{1000, 1000, 500},
+		},
+		{
+			// each row has one []byte bind parameter
+			d:     dialect.SQLite3,
+			stmt:  &InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(2000, []byte{1})},
+			sizes: []int{999, 999, 2},
+		},
+		{
+			d:     dialect.PostgreSQL,
+			stmt:  &InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(2000, []byte{1})},
+			sizes: []int{2000},
+		},
+		{
+			// `INSERT INTO "t" ("a","b") VALUES ` is 33 bytes, `(0,'x'), ` is 9 bytes
+			d:     dialect.PostgreSQL,
+			stmt:  (&InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(10, "x")}).Batch(BatchLimit{MaxBytes: 60}),
+			sizes: []int{3, 3, 3, 1},
+		},
+		{
+			d:     dialect.MySQL,
+			stmt:  (&InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(10, "x")}).Batch(BatchLimit{MaxRows: 4}),
+			sizes: []int{4, 4, 2},
+		},
+		{
+			// every value is a bind parameter
+			d:     dialect.MSSQL,
+			stmt:  &InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(1200, "x")},
+			bind:  true,
+			sizes: []int{1000, 200},
+		},
+		{
+			d:     dialect.SQLite3,
+			stmt:  &InsertStmt{Table: "t", Column: []string{"a", "b"}, Value: rows(1200, "x")},
+			bind:  true,
+			sizes: []int{499, 499, 202},
+		},
+	} {
+		batches, err := test.stmt.batches(test.d, test.bind)
+		require.NoError(t, err)
+		require.Equal(t, test.sizes, sizes(batches))
+	}
+}
+
+func TestInsertRecords(t *testing.T) {
+	people := []dbrPerson{
+		{Name: "a", Email: "a@example.com"},
+		{Name: "b", Email: "b@example.com"},
+	}
+	stmt := InsertInto("dbr_people").Columns("name", "email").Records(people)
+	require.Equal(t, [][]interface{}{{"a", "a@example.com"}, {"b", "b@example.com"}}, stmt.Value)
+	require.Nil(t, stmt.RecordID)
+
+	stmt = InsertInto("dbr_people").Columns("name", "email").Records([][]string{{"c", "c@example.com"}})
+	require.Equal(t, [][]interface{}{{"c", "c@example.com"}}, stmt.Value)
+
+	for _, test := range []struct {
+		stmt *InsertStmt
+		err  error
+	}{
+		{
+			stmt: InsertInto("dbr_people").Columns("name").Records(people[0]),
+			err:  ErrInvalidPointer,
+		},
+		{
+			stmt: InsertInto("dbr_people").Columns("name").Records([]interface{}{"a"}),
+			err:  ErrInvalidPointer,
+		},
+		{
+			stmt: InsertInto("dbr_people").Columns("name").Records([]*dbrPerson{nil}),
+			err:  ErrInvalidPointer,
+		},
+		{
+			// the id field of a copy cannot be set
+			stmt: InsertInto("dbr_people").Columns("name").Records([]interface{}{people[0]}),
+			err:  ErrInvalidPointer,
+		},
+		{
+			stmt: InsertInto("dbr_people").Records(people),
+			err:  ErrColumnNotSpecified,
+		},
+	} {
+		_, _, err := test.stmt.ToSQL(dialect.MySQL)
+		require.Equal(t, test.err, err)
+		_, err = test.stmt.ExecBatch()
+		require.Equal(t, test.err, err)
+	}
+}
+
+func TestInsertExecBatch(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "dbr_people" ("name","email") VALUES ('a','a@example.com'), ('b','b@example.com')`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO "dbr_people" ("name","email") VALUES ('c','c@example.com')`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "dbr_people" ("name","email") VALUES ('a','a@example.com'), ('b','b@example.com') RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(`INSERT INTO "dbr_people" ("name","email") VALUES ('c','c@example.com') RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	people := []dbrPerson{
+		{Name: "a", Email: "a@example.com"},
+		{Name: "b", Email: "b@example.com"},
+		{Name: "c", Email: "c@example.com"},
+	}
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	defer tx.RollbackUnlessCommitted()
+
+	res, err := tx.InsertInto("dbr_people").
+		Columns("name", "email").
+		Records(people).
+		Batch(BatchLimit{MaxRows: 2}).
+		ExecBatch()
+	require.NoError(t, err)
+	require.Equal(t, BatchResult{RowsAffected: 3, Batches: 2}, res)
+
+	res, err = tx.InsertInto("dbr_people").
+		Columns("name", "email").
+		Records(people).
+		Returning("id").
+		Batch(BatchLimit{MaxRows: 2}).
+		ExecBatch()
+	require.NoError(t, err)
+	require.Equal(t, BatchResult{RowsAffected: 3, ID: []int64{1, 2, 3}, Batches: 2}, res)
+
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
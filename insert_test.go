@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/gocraft/dbr/v2/dialect"
@@ -58,3 +59,53 @@ func BenchmarkInsertRecordSQL(b *testing.B) {
 		}).Build(dialect.MySQL, buf)
 	}
 }
+
+func TestOracleReturningInto(t *testing.T) {
+	var id int64
+	var name string
+	query, value, err := InsertInto("dbr_people").
+		Columns("name", "key").
+		Values("a", []byte{1}).
+		ReturningInto("id", &id).
+		ReturningInto("name", &name).
+		ToSQL(dialect.Oracle)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "dbr_people" ("name","key") VALUES (:1,:2) RETURNING "id","name" INTO :3,:4`, query)
+	require.Equal(t, []interface{}{"a", []byte{1}, sql.Out{Dest: &id}, sql.Out{Dest: &name}}, value)
+
+	// values are interpolated, but output parameters are always bound
+	i := interpolator{
+		Buffer:       NewBuffer(),
+		Dialect:      dialect.Oracle,
+		IgnoreBinary: true,
+	}
+	err = i.encodePlaceholder(Update("dbr_people").Set("name", "b").Where(Eq("id", 1)).ReturningInto("id", &id), true)
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE "dbr_people" SET "name" = 'b' WHERE ("id" = 1) RETURNING "id" INTO :1`, i.String())
+	require.Equal(t, []interface{}{sql.Out{Dest: &id}}, i.Value())
+
+	_, _, err = InsertInto("dbr_people").Columns("name").Values("a").Returning("id").ToSQL(dialect.Oracle)
+	require.Equal(t, ErrPlaceholderCount, err)
+}
+
+func TestOracleInsertAll(t *testing.T) {
+	query, value, err := InsertInto("dbr_people").
+		Columns("name", "email").
+		Values("a", "a@example.com").
+		Values("b", "b@example.com").
+		ToSQL(dialect.Oracle)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT ALL INTO "dbr_people" ("name","email") VALUES (:1,:2) INTO "dbr_people" ("name","email") VALUES (:3,:4) SELECT 1 FROM DUAL`, query)
+	require.Equal(t, []interface{}{"a", "a@example.com", "b", "b@example.com"}, value)
+
+	// one row is a plain insert
+	query, _, err = InsertInto("dbr_people").Columns("name").Values("a").ToSQL(dialect.Oracle)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "dbr_people" ("name") VALUES (:1)`, query)
+
+	var id int64
+	_, _, err = InsertInto("dbr_people").Columns("name").Values("a").Values("b").
+		ReturningInto("id", &id).
+		ToSQL(dialect.Oracle)
+	require.Equal(t, ErrNotSupported, err)
+}
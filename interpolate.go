@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"reflect"
 	"strconv"
@@ -12,7 +13,14 @@ type interpolator struct {
 	Buffer
 	Dialect
 	IgnoreBinary bool
-	N            int
+	// Bind replaces every value with a dialect placeholder, and collects
+	// values as driver arguments instead of interpolating them.
+	Bind bool
+	// Compile records the position of every template parameter in Slot
+	// instead of building it.
+	Compile bool
+	Slot    []templateSlot
+	N       int
 }
 
 // InterpolateForDialect replaces placeholder
@@ -29,6 +37,8 @@ type interpolator struct {
 // which means we interpolate all of those question marks with
 // their arguments before they get to MySQL.
 // The result of this is that it's way faster, and just as secure.
+// For hot queries that benefit from cached plans, prepared statements
+// can be enabled with StmtCache.
 //
 // Check out these benchmarks from https://github.com/tyler-smith/golang-sql-benchmark.
 func InterpolateForDialect(query string, value []interface{}, d Dialect) (string, error) {
@@ -43,6 +53,24 @@ func InterpolateForDialect(query string, value []interface{}, d Dialect) (string
 	return i.String(), nil
 }
 
+// ToSQL builds builder in dialect d, and returns the query with dialect
+// placeholders like ?, $1 or @p1, and the values to bind to them.
+//
+// Slices are expanded to one placeholder per element, so the result can be
+// run with database/sql or any other driver that accepts the same placeholders.
+func ToSQL(builder Builder, d Dialect) (string, []interface{}, error) {
+	i := interpolator{
+		Buffer:  NewBuffer(),
+		Dialect: d,
+		Bind:    true,
+	}
+	err := i.encodePlaceholder(builder, true)
+	if err != nil {
+		return "", nil, err
+	}
+	return i.String(), i.Value(), nil
+}
+
 var escapedPlaceholder = strings.Repeat(placeholder, 2)
 
 func (i *interpolator) interpolate(query string, value []interface{}, topLevel bool) error {
@@ -66,7 +94,7 @@ func (i *interpolator) interpolate(query string, value []interface{}, topLevel b
 		}
 
 		i.WriteString(query[:index])
-		if _, ok := value[valueIndex].([]byte); ok && i.IgnoreBinary {
+		if isBindOnly(value[valueIndex]) && i.IgnoreBinary {
 			i.WriteString(i.Placeholder(i.N))
 			i.N++
 			i.WriteValue(value[valueIndex])
@@ -94,7 +122,22 @@ var (
 	typeTime = reflect.TypeOf(time.Time{})
 )
 
+// isBindOnly reports whether value is sent to the driver as is:
+// binary data, or output parameters like `RETURNING ... INTO`.
+func isBindOnly(value interface{}) bool {
+	switch value.(type) {
+	case []byte, sql.Out:
+		return true
+	}
+	return false
+}
+
 func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error {
+	if param, ok := value.(*templateParam); ok && i.Compile {
+		i.Slot = append(i.Slot, templateSlot{param: param, pos: len(i.String())})
+		return nil
+	}
+
 	if builder, ok := value.(Builder); ok {
 		pbuf := NewBuffer()
 		err := builder.Build(i.Dialect, pbuf)
@@ -119,6 +162,10 @@ func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error
 		return nil
 	}
 
+	if i.Bind {
+		return i.bind(value, topLevel)
+	}
+
 	if valuer, ok := value.(driver.Valuer); ok {
 		// get driver.Valuer's data
 		var err error
@@ -185,3 +232,31 @@ func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error
 	}
 	return ErrNotSupported
 }
+
+// bind writes a placeholder for value, and expands slices for IN.
+func (i *interpolator) bind(value interface{}, topLevel bool) error {
+	if _, ok := value.(driver.Valuer); !ok && value != nil {
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+			if v.Len() == 0 {
+				return ErrInvalidSliceLength
+			}
+			i.WriteString("(")
+			for n := 0; n < v.Len(); n++ {
+				if n > 0 {
+					i.WriteString(",")
+				}
+				err := i.encodePlaceholder(v.Index(n).Interface(), topLevel)
+				if err != nil {
+					return err
+				}
+			}
+			i.WriteString(")")
+			return nil
+		}
+	}
+	i.WriteString(i.Placeholder(i.N))
+	i.N++
+	i.WriteValue(value)
+	return nil
+}
@@ -55,6 +55,51 @@ func TestInterpolateIgnoreBinary(t *testing.T) {
 	}
 }
 
+func TestInterpolateBind(t *testing.T) {
+	for _, test := range []struct {
+		d         Dialect
+		query     string
+		value     []interface{}
+		wantQuery string
+		wantValue []interface{}
+	}{
+		{
+			d:         dialect.MySQL,
+			query:     "? ?",
+			value:     []interface{}{1, "a"},
+			wantQuery: "? ?",
+			wantValue: []interface{}{1, "a"},
+		},
+		{
+			d:         dialect.PostgreSQL,
+			query:     "? IN ? ?",
+			value:     []interface{}{nil, []int{1, 2}, []byte{3}},
+			wantQuery: "$1 IN ($2,$3) $4",
+			wantValue: []interface{}{nil, 1, 2, []byte{3}},
+		},
+		{
+			d:         dialect.MSSQL,
+			query:     "? ??",
+			value:     []interface{}{Expr("x = ?", NewNullInt64(1))},
+			wantQuery: "x = @p1 ?",
+			wantValue: []interface{}{NewNullInt64(1)},
+		},
+	} {
+		i := interpolator{
+			Buffer:       NewBuffer(),
+			Dialect:      test.d,
+			IgnoreBinary: true,
+			Bind:         true,
+		}
+
+		err := i.interpolate(test.query, test.value, true)
+		require.NoError(t, err)
+
+		require.Equal(t, test.wantQuery, i.String())
+		require.Equal(t, test.wantValue, i.Value())
+	}
+}
+
 func TestInterpolateForDialect(t *testing.T) {
 	for _, test := range []struct {
 		query string
@@ -307,3 +352,54 @@ x' or 1=1 or 'x'='y
 //*
 */*
 `
+
+func TestToSQL(t *testing.T) {
+	sub := Select("id").From("dbr_people").Where(Eq("name", "a"))
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d:     dialect.MySQL,
+			query: "SELECT * FROM suggestions WHERE (`id` IN (?,?)) AND (`person_id` = (SELECT id FROM dbr_people WHERE (`name` = ?)))",
+		},
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT * FROM suggestions WHERE ("id" IN ($1,$2)) AND ("person_id" = (SELECT id FROM dbr_people WHERE ("name" = $3)))`,
+		},
+		{
+			d:     dialect.SQLite3,
+			query: `SELECT * FROM suggestions WHERE ("id" IN (?,?)) AND ("person_id" = (SELECT id FROM dbr_people WHERE ("name" = ?)))`,
+		},
+		{
+			d:     dialect.MSSQL,
+			query: `SELECT * FROM suggestions WHERE ("id" IN (@p1,@p2)) AND ("person_id" = (SELECT id FROM dbr_people WHERE ("name" = @p3)))`,
+		},
+	} {
+		query, value, err := Select("*").From("suggestions").
+			Where(Eq("id", []int{1, 2})).
+			Where(Eq("person_id", sub)).
+			ToSQL(test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+		require.Equal(t, []interface{}{1, 2, "a"}, value)
+	}
+
+	query, value, err := InsertInto("dbr_people").Columns("name", "data").Values("a", []byte{1}).ToSQL(dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "dbr_people" ("name","data") VALUES ($1,$2)`, query)
+	require.Equal(t, []interface{}{"a", []byte{1}}, value)
+
+	query, value, err = Update("dbr_people").Set("name", "a").Where(Eq("id", 1)).ToSQL(dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `dbr_people` SET `name` = ? WHERE (`id` = ?)", query)
+	require.Equal(t, []interface{}{"a", 1}, value)
+
+	query, value, err = DeleteFrom("dbr_people").Where(Eq("id", 1)).ToSQL(dialect.MSSQL)
+	require.NoError(t, err)
+	require.Equal(t, `DELETE FROM "dbr_people" WHERE ("id" = @p1)`, query)
+	require.Equal(t, []interface{}{1}, value)
+
+	_, _, err = Select("*").From("suggestions").Where("id IN ?", []int{}).ToSQL(dialect.MySQL)
+	require.Equal(t, ErrInvalidSliceLength, err)
+}
@@ -3,6 +3,7 @@ package dbr
 import (
 	"database/sql"
 	"reflect"
+	"time"
 )
 
 // Iterator is an interface to iterate over the result of a sql query
@@ -28,12 +29,17 @@ type recordMeta struct {
 
 func (m *recordMeta) scan(rows *sql.Rows, value interface{}) (err error) {
 	var v, elem, keyElem reflect.Value
+	var g *nullGroup
 
+	if nl, ok := value.(nilStructLoader); ok {
+		value = nl.v
+	}
 	if il, ok := value.(interfaceLoader); ok {
 		v = reflect.ValueOf(il.v)
 	} else {
 		v = reflect.ValueOf(value)
 	}
+	v = v.Elem()
 
 	if m.elemType != nil {
 		elem = reflectAlloc(m.elemType)
@@ -46,7 +52,11 @@ func (m *recordMeta) scan(rows *sql.Rows, value interface{}) (err error) {
 	}
 
 	if m.isMap {
+		if m.ts.nilStruct && elem.Kind() == reflect.Ptr && isNestedStruct(elem.Type()) {
+			g = m.ts.beginNull(reflect.Value{})
+		}
 		err = m.ts.findPtr(elem, m.columns[1:], m.ptr[1:])
+		m.ts.endNull(g)
 		if err != nil {
 			return
 		}
@@ -71,8 +81,10 @@ func (m *recordMeta) scan(rows *sql.Rows, value interface{}) (err error) {
 	}
 	err = rows.Scan(m.ptr...)
 	if err != nil {
+		m.ts.resetNull()
 		return
 	}
+	m.ts.resolveNull()
 	for i := range m.ptr {
 		m.ptr[i] = nil
 	}
@@ -82,10 +94,16 @@ func (m *recordMeta) scan(rows *sql.Rows, value interface{}) (err error) {
 	} else if m.isMapOfSlices {
 		s := v.MapIndex(keyElem)
 		if !s.IsValid() {
-			s = reflect.Zero(v.Type().Elem())
+			s = reflect.MakeSlice(v.Type().Elem(), 0, 0)
 		}
-		v.SetMapIndex(keyElem, reflect.Append(s, elem))
+		if g == nil || !g.null {
+			s = reflect.Append(s, elem)
+		}
+		v.SetMapIndex(keyElem, s)
 	} else if m.isMap {
+		if g != nil && g.null {
+			elem = reflect.Zero(elem.Type())
+		}
 		v.SetMapIndex(keyElem, elem)
 	}
 	return
@@ -97,6 +115,10 @@ func newRecordMeta(column []string, value interface{}) (meta *recordMeta, err er
 	var v reflect.Value
 	var elemType reflect.Type
 
+	nl, nilStruct := value.(nilStructLoader)
+	if nilStruct {
+		value = nl.v
+	}
 	if il, ok := value.(interfaceLoader); ok {
 		v = reflect.ValueOf(il.v)
 		elemType = il.typ
@@ -117,6 +139,7 @@ func newRecordMeta(column []string, value interface{}) (meta *recordMeta, err er
 	}
 
 	s := newTagStore()
+	s.nilStruct = nilStruct
 	return &recordMeta{
 		elemType:      elemType,
 		isSlice:       isSlice,
@@ -133,6 +156,12 @@ type iteratorInternals struct {
 	rows       *sql.Rows
 	recordMeta *recordMeta
 	columns    []string
+
+	// for QueryError
+	stmt  *SelectStmt
+	query string
+	bound bool
+	start time.Time
 }
 
 // Next prepares the next result row for reading with the Scan method.
@@ -152,8 +181,11 @@ func (i *iteratorInternals) Scan(value interface{}) (err error) {
 			return err
 		}
 	}
-	return i.recordMeta.scan(i.rows, value)
-
+	err = i.recordMeta.scan(i.rows, value)
+	if err != nil && i.stmt != nil {
+		return newQueryError("scan", i.stmt, i.stmt.Dialect, i.query, i.bound, i.start, err)
+	}
+	return err
 }
 
 // Close frees ressources created by the request execution.
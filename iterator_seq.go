@@ -0,0 +1,48 @@
+//go:build go1.23
+
+package dbr
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns a sequence of rows and errors for range-over-func.
+// Rows are closed when the loop ends, even if it ends early.
+// An error is the last element of the sequence.
+func (i *TypedIterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer i.Close()
+		for i.Next() {
+			if !yield(i.Value(), nil) {
+				return
+			}
+		}
+		if err := i.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// IterateSeq runs b and returns a sequence of its rows, like
+//
+//	for person, err := range dbr.IterateSeq[Person](ctx, stmt) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//
+// The query runs when the loop starts.
+func IterateSeq[T any](ctx context.Context, b *SelectStmt) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		it, err := Iterate[T](ctx, b)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		it.All()(yield)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build go1.23
+
+package dbr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterateSeq(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+	stmt := sess.Select("*").From("dbr_people")
+
+	mock.ExpectQuery("SELECT").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	var people []dbrPerson
+	for person, err := range IterateSeq[dbrPerson](context.Background(), stmt) {
+		require.NoError(t, err)
+		people = append(people, person)
+	}
+	require.Equal(t, []dbrPerson{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}}, people)
+
+	// closed when the loop ends early
+	mock.ExpectQuery("SELECT").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2)).
+		RowsWillBeClosed()
+	var ids []int64
+	for id, err := range IterateSeq[int64](context.Background(), stmt) {
+		require.NoError(t, err)
+		ids = append(ids, id)
+		break
+	}
+	require.Equal(t, []int64{1}, ids)
+
+	// query error
+	cause := errors.New("query")
+	mock.ExpectQuery("SELECT").WillReturnError(cause)
+	n := 0
+	for _, err := range IterateSeq[int64](context.Background(), stmt) {
+		require.ErrorIs(t, err, cause)
+		n++
+	}
+	require.Equal(t, 1, n)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
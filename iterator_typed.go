@@ -0,0 +1,65 @@
+package dbr
+
+import "context"
+
+// TypedIterator iterates over rows of a query, and loads each row into T.
+// T is loaded like an element of a slice in Load.
+//
+// Rows are closed when Next returns false, so Close is only needed
+// when the iteration stops early.
+type TypedIterator[T any] struct {
+	it *iteratorInternals
+	// row is scanned like the slice of LoadAll, so that
+	// a slice or map T is one value instead of the rows.
+	row []T
+	err error
+}
+
+// Iterate runs b and returns a TypedIterator over its rows.
+func Iterate[T any](ctx context.Context, b *SelectStmt) (*TypedIterator[T], error) {
+	it, err := b.IterateContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedIterator[T]{it: it.(*iteratorInternals)}, nil
+}
+
+// Next loads the next row. It returns false after the last row,
+// or if there is an error.
+func (i *TypedIterator[T]) Next() bool {
+	if i.err != nil || !i.it.Next() {
+		return false
+	}
+	// recordMeta of the first row is reused for the rest.
+	i.row = i.row[:0]
+	err := i.it.Scan(&i.row)
+	if err != nil {
+		i.err = err
+		i.it.rows.Close()
+		return false
+	}
+	return true
+}
+
+// Value returns the row that is loaded by Next.
+func (i *TypedIterator[T]) Value() T {
+	var zero T
+	if len(i.row) == 0 {
+		return zero
+	}
+	return i.row[0]
+}
+
+// Err returns the error that stopped the iteration, or nil.
+func (i *TypedIterator[T]) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.it.Err()
+}
+
+// Close closes the rows. An error that stopped the iteration
+// is returned by Err instead.
+func (i *TypedIterator[T]) Close() error {
+	return i.it.Close()
+}
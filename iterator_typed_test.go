@@ -0,0 +1,66 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedIterator(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+	stmt := sess.Select("*").From("dbr_people")
+
+	mock.ExpectQuery("SELECT").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b")).
+		RowsWillBeClosed()
+	it, err := Iterate[dbrPerson](context.Background(), stmt)
+	require.NoError(t, err)
+	var people []dbrPerson
+	for it.Next() {
+		people = append(people, it.Value())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []dbrPerson{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}}, people)
+
+	// scan error
+	mock.ExpectQuery("SELECT").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow("a").AddRow(3)).
+		RowsWillBeClosed()
+	ids, err := Iterate[int64](context.Background(), stmt)
+	require.NoError(t, err)
+	require.True(t, ids.Next())
+	require.Equal(t, int64(1), ids.Value())
+	require.False(t, ids.Next())
+	var queryErr *QueryError
+	require.ErrorAs(t, ids.Err(), &queryErr)
+	require.Equal(t, "scan", queryErr.Op)
+	require.NoError(t, ids.Close())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTypedIteratorSlice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.PostgreSQL, nil).NewSession(nil)
+
+	// a slice is one value of an array column, not the rows.
+	mock.ExpectQuery("SELECT refs FROM posts").
+		WillReturnRows(sqlmock.NewRows([]string{"refs"}).AddRow([]byte("{1,2}")).AddRow([]byte("{3}"))).
+		RowsWillBeClosed()
+	it, err := Iterate[[]int64](context.Background(), sess.Select("refs").From("posts"))
+	require.NoError(t, err)
+	var refs [][]int64
+	for it.Next() {
+		refs = append(refs, it.Value())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, [][]int64{{1, 2}, {3}}, refs)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
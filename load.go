@@ -40,6 +40,10 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 	var v reflect.Value
 	var elemType reflect.Type
 
+	nl, nilStruct := value.(nilStructLoader)
+	if nilStruct {
+		value = nl.v
+	}
 	if il, ok := value.(interfaceLoader); ok {
 		v = reflect.ValueOf(il.v)
 		elemType = il.typ
@@ -60,9 +64,11 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 	}
 
 	s := newTagStore()
+	s.nilStruct = nilStruct
 	count := 0
 	for rows.Next() {
 		var elem, keyElem reflect.Value
+		var g *nullGroup
 
 		if elemType != nil {
 			elem = reflectAlloc(elemType)
@@ -75,7 +81,11 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 		}
 
 		if isMap {
+			if nilStruct && elem.Kind() == reflect.Ptr && isNestedStruct(elem.Type()) {
+				g = s.beginNull(reflect.Value{})
+			}
 			err := s.findPtr(elem, column[1:], ptr[1:])
+			s.endNull(g)
 			if err != nil {
 				return 0, err
 			}
@@ -100,8 +110,10 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 		}
 		err = rows.Scan(ptr...)
 		if err != nil {
+			s.resetNull()
 			return 0, err
 		}
+		s.resolveNull()
 		for i := range ptr {
 			ptr[i] = nil
 		}
@@ -113,10 +125,16 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 		} else if isMapOfSlices {
 			s := v.MapIndex(keyElem)
 			if !s.IsValid() {
-				s = reflect.Zero(v.Type().Elem())
+				s = reflect.MakeSlice(v.Type().Elem(), 0, 0)
 			}
-			v.SetMapIndex(keyElem, reflect.Append(s, elem))
+			if g == nil || !g.null {
+				s = reflect.Append(s, elem)
+			}
+			v.SetMapIndex(keyElem, s)
 		} else if isMap {
+			if g != nil && g.null {
+				elem = reflect.Zero(elem.Type())
+			}
 			v.SetMapIndex(keyElem, elem)
 		} else {
 			break
@@ -0,0 +1,127 @@
+package dbr
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+type nilStructLoader struct {
+	v interface{}
+}
+
+// NilStructLoader wraps value so that Load leaves a pointer-to-struct field nil
+// if all columns that are mapped into it are NULL, like the columns of
+// a LEFT JOIN without a match.
+//
+// If value is a map or a map of slices whose values are pointers to structs,
+// such rows are loaded as a nil value, or are not appended to the slice.
+func NilStructLoader(value interface{}) interface{} {
+	return nilStructLoader{value}
+}
+
+// nullDest is a scan destination in a nilable struct
+// that records whether the column is NULL.
+type nullDest struct {
+	scanner sql.Scanner
+	// holder is **T that is scanned instead of target
+	// if target is not a sql.Scanner.
+	holder reflect.Value
+	target reflect.Value
+	valid  bool
+}
+
+func (d *nullDest) Scan(src interface{}) error {
+	d.valid = src != nil
+	return d.scanner.Scan(src)
+}
+
+// nullGroup is the columns that are mapped into a nilable struct.
+type nullGroup struct {
+	// field is the pointer to the struct,
+	// or invalid if it is the value of a map.
+	field      reflect.Value
+	start, end int
+	null       bool
+}
+
+// isNilable reports whether field is a pointer-to-struct
+// that is set to nil if all its columns are NULL.
+func (s *tagStore) isNilable(field reflect.Value) bool {
+	return s.nilStruct &&
+		field.Kind() == reflect.Ptr &&
+		field.CanSet() &&
+		isNestedStruct(field.Type())
+}
+
+// beginNull starts a nullGroup. Destinations until endNull are tracked.
+// field is set to a new struct, so that the struct of a reused
+// destination, which may be shared with a previous row, is not changed.
+func (s *tagStore) beginNull(field reflect.Value) *nullGroup {
+	if field.IsValid() {
+		field.Set(reflect.New(field.Type().Elem()))
+	}
+	s.depth++
+	return &nullGroup{
+		field: field,
+		start: len(s.dest),
+	}
+}
+
+func (s *tagStore) endNull(g *nullGroup) {
+	if g == nil {
+		return
+	}
+	s.depth--
+	g.end = len(s.dest)
+	// inner groups end first, so they are resolved first.
+	s.group = append(s.group, g)
+}
+
+// nullable returns a destination that tracks NULL instead of dest
+// if it is in a nullGroup.
+func (s *tagStore) nullable(dest interface{}) interface{} {
+	if s.depth == 0 {
+		return dest
+	}
+	d := new(nullDest)
+	s.dest = append(s.dest, d)
+	if scanner, ok := dest.(sql.Scanner); ok {
+		d.scanner = scanner
+		return d
+	}
+	// database/sql sets *T to nil for NULL.
+	d.target = reflect.ValueOf(dest).Elem()
+	d.holder = reflect.New(reflect.TypeOf(dest))
+	return d.holder.Interface()
+}
+
+// resolveNull is called after a row is scanned,
+// and sets structs whose columns are all NULL to nil.
+func (s *tagStore) resolveNull() {
+	for _, d := range s.dest {
+		if d.holder.IsValid() && !d.holder.Elem().IsNil() {
+			d.valid = true
+			d.target.Set(d.holder.Elem().Elem())
+		}
+	}
+	for _, g := range s.group {
+		g.null = true
+		for _, d := range s.dest[g.start:g.end] {
+			if d.valid {
+				g.null = false
+				break
+			}
+		}
+		if g.null && g.field.IsValid() {
+			g.field.Set(reflect.Zero(g.field.Type()))
+		}
+	}
+	s.resetNull()
+}
+
+// resetNull forgets the tracked destinations of a row.
+// It is called after a row is resolved, or fails to scan.
+func (s *tagStore) resetNull() {
+	s.dest = s.dest[:0]
+	s.group = s.group[:0]
+}
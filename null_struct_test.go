@@ -0,0 +1,105 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type nullStructAddress struct {
+	City   string
+	Street NullString
+}
+
+type nullStructPerson struct {
+	ID      int64
+	Name    string
+	Address *nullStructAddress `db:"address"`
+}
+
+func TestNilStructLoader(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "address.city", "address.street"}).
+			AddRow(1, "a", "x", nil).
+			AddRow(2, "b", nil, nil)
+	}
+	stmt := sess.Select("*").From("dbr_people")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(rows())
+	var people []nullStructPerson
+	_, err = stmt.Load(NilStructLoader(&people))
+	require.NoError(t, err)
+	require.Equal(t, []nullStructPerson{
+		{ID: 1, Name: "a", Address: &nullStructAddress{City: "x"}},
+		{ID: 2, Name: "b"},
+	}, people)
+
+	// iterator
+	mock.ExpectQuery("SELECT").WillReturnRows(rows())
+	it, err := stmt.Iterate()
+	require.NoError(t, err)
+	people = nil
+	for it.Next() {
+		var person nullStructPerson
+		require.NoError(t, it.Scan(NilStructLoader(&person)))
+		people = append(people, person)
+	}
+	require.NoError(t, it.Close())
+	require.NotNil(t, people[0].Address)
+	require.Nil(t, people[1].Address)
+
+	// iterator that reuses one struct, where the first row allocates Address
+	mock.ExpectQuery("SELECT").WillReturnRows(rows())
+	it, err = stmt.Iterate()
+	require.NoError(t, err)
+	people = nil
+	var person nullStructPerson
+	for it.Next() {
+		require.NoError(t, it.Scan(NilStructLoader(&person)))
+		people = append(people, person)
+	}
+	require.NoError(t, it.Close())
+	require.Equal(t, []nullStructPerson{
+		{ID: 1, Name: "a", Address: &nullStructAddress{City: "x"}},
+		{ID: 2, Name: "b"},
+	}, people)
+
+	// map
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "city", "street"}).
+		AddRow(1, "x", "y").
+		AddRow(2, nil, nil))
+	var addresses map[int64]*nullStructAddress
+	_, err = stmt.Load(NilStructLoader(&addresses))
+	require.NoError(t, err)
+	require.Equal(t, map[int64]*nullStructAddress{
+		1: {City: "x", Street: NewNullString("y")},
+		2: nil,
+	}, addresses)
+
+	// map of slices
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "city", "street"}).
+		AddRow(1, "x", nil).
+		AddRow(1, "y", nil).
+		AddRow(2, nil, nil))
+	var addressLists map[int64][]*nullStructAddress
+	_, err = stmt.Load(NilStructLoader(&addressLists))
+	require.NoError(t, err)
+	require.Equal(t, map[int64][]*nullStructAddress{
+		1: {{City: "x"}, {City: "y"}},
+		2: {},
+	}, addressLists)
+
+	// without NilStructLoader, nil pointer fields are not loaded
+	mock.ExpectQuery("SELECT").WillReturnRows(rows())
+	people = nil
+	_, err = stmt.Load(&people)
+	require.NoError(t, err)
+	require.Nil(t, people[0].Address)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
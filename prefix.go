@@ -0,0 +1,79 @@
+package dbr
+
+import "reflect"
+
+// PrefixColumns returns the columns of struct value in table,
+// aliased with prefix like `table.name AS "prefix.name"`.
+//
+// Load maps such columns to a nested struct field whose tag is prefix,
+// so that joined tables can have columns with the same name:
+//
+//	type userOrder struct {
+//		User  User  `db:"user"`
+//		Order Order `db:"order"`
+//	}
+//
+//	sess.Select(append(
+//		dbr.PrefixColumns("u", "user", User{}),
+//		dbr.PrefixColumns("o", "order", Order{})...,
+//	)...).
+//		From(dbr.I("users").As("u")).
+//		Join(dbr.I("orders").As("o"), "o.user_id = u.id")
+//
+// Embedded structs are flattened, and other struct fields that are not
+// scanned as a single value are skipped.
+func PrefixColumns(table, prefix string, value interface{}) []interface{} {
+	var column []interface{}
+	for _, name := range structColumns(newTagStore(), reflect.TypeOf(value)) {
+		col := name
+		if table != "" {
+			col = table + "." + name
+		}
+		column = append(column, prefixColumn(col, prefix+"."+name))
+	}
+	return column
+}
+
+func prefixColumn(column, alias string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(d.QuoteIdent(column))
+		buf.WriteString(" AS ")
+		// QuoteIdent splits names by dot.
+		quote := d.QuoteIdent("")
+		buf.WriteString(quote[:len(quote)/2] + alias + quote[len(quote)/2:])
+		return nil
+	})
+}
+
+// structColumns returns the names of columns that Load maps to struct type t.
+func structColumns(s *tagStore, t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var column []string
+	for i, tag := range s.get(t) {
+		if tag == "" {
+			continue
+		}
+		field := t.Field(i)
+		if isNestedStruct(field.Type) {
+			if field.Anonymous {
+				column = append(column, structColumns(s, field.Type)...)
+			}
+			continue
+		}
+		column = append(column, tag)
+	}
+	return column
+}
+
+// isNestedStruct reports whether t is a struct that is not scanned as a single value.
+func isNestedStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct &&
+		t != typeTime &&
+		!reflect.PtrTo(t).Implements(typeScanner) &&
+		!t.Implements(typeValuer)
+}
@@ -0,0 +1,108 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type prefixUser struct {
+	ID   int64
+	Name string
+}
+
+type prefixOrder struct {
+	ID        int64
+	UserID    int64
+	CreatedAt time.Time
+}
+
+type prefixUserOrder struct {
+	User  prefixUser  `db:"user"`
+	Order prefixOrder `db:"order"`
+}
+
+func TestPrefixColumns(t *testing.T) {
+	type base struct {
+		ID int64
+	}
+	type model struct {
+		base
+		Name  string
+		Owner prefixUser
+		Skip  string `db:"-"`
+	}
+	column := PrefixColumns("m", "model", model{})
+	stmt := Select(column...).From(I("models").As("m"))
+
+	for _, test := range []struct {
+		d    Dialect
+		want string
+	}{
+		{
+			d:    dialect.MySQL,
+			want: "SELECT `m`.`id` AS `model.id`, `m`.`name` AS `model.name` FROM `models` AS `m`",
+		},
+		{
+			d:    dialect.PostgreSQL,
+			want: `SELECT "m"."id" AS "model.id", "m"."name" AS "model.name" FROM "models" AS "m"`,
+		},
+	} {
+		query, err := InterpolateForDialect("?", []interface{}{stmt}, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.want, query)
+	}
+
+	require.Len(t, PrefixColumns("", "user", &prefixUser{}), 2)
+}
+
+func TestLoadPrefix(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user.id", "user.name", "order.id", "order.user_id", "order.created_at"}).
+			AddRow(1, "a", 10, 1, now).
+			AddRow(1, "a", 11, 1, now)
+	}
+	want := []prefixUserOrder{
+		{User: prefixUser{ID: 1, Name: "a"}, Order: prefixOrder{ID: 10, UserID: 1, CreatedAt: now}},
+		{User: prefixUser{ID: 1, Name: "a"}, Order: prefixOrder{ID: 11, UserID: 1, CreatedAt: now}},
+	}
+	stmt := sess.Select(append(
+		PrefixColumns("u", "user", prefixUser{}),
+		PrefixColumns("o", "order", prefixOrder{})...,
+	)...).
+		From(I("users").As("u")).
+		Join(I("orders").As("o"), "o.user_id = u.id")
+
+	mock.ExpectQuery("SELECT `u`.`id` AS `user.id`, `u`.`name` AS `user.name`, `o`.`id` AS `order.id`").WillReturnRows(rows())
+	var got []prefixUserOrder
+	_, err = stmt.Load(&got)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(rows())
+	var one prefixUserOrder
+	err = stmt.LoadOne(&one)
+	require.NoError(t, err)
+	require.Equal(t, want[0], one)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(rows())
+	it, err := Iterate[prefixUserOrder](context.Background(), stmt)
+	require.NoError(t, err)
+	got = nil
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, want, got)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,357 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// RelationKind is how a parent and its children are related.
+type RelationKind uint8
+
+const (
+	// HasMany loads children whose ForeignKey is the Key of the parent
+	// into a slice field.
+	HasMany RelationKind = iota + 1
+	// BelongsTo loads the child whose Key is the ForeignKey of the parent
+	// into a struct or pointer field.
+	BelongsTo
+)
+
+// Relation describes the children of a struct field to preload.
+//
+// Zero fields are read from the `dbr` tag of the field, like
+//
+//	type User struct {
+//		ID     int64
+//		Orders []Order `db:"-" dbr:"has_many,table=orders,foreign_key=user_id"`
+//	}
+//
+//	type Order struct {
+//		ID     int64
+//		UserID int64
+//		User   *User `db:"-" dbr:"belongs_to,table=users,foreign_key=user_id"`
+//	}
+//
+// Relation fields are usually tagged with `db:"-"`, so that columns
+// are not loaded into them.
+type Relation struct {
+	// Field is the name of the struct field that receives the children.
+	Field string
+	Kind  RelationKind
+	// Table is the table of the children.
+	Table string
+	// Column is the columns of the children to select. Empty means all columns.
+	Column []string
+	// ForeignKey is the column that refers to the other side:
+	// a column of the children for HasMany, or a column of the parent for BelongsTo.
+	ForeignKey string
+	// Key is the column that ForeignKey refers to. Empty means id.
+	Key string
+	// Where and OrderBy are added to the query of the children.
+	Where   []Builder
+	OrderBy []string
+	// Preload is the relations of the children.
+	Preload []Relation
+}
+
+// Preload loads relations of parents, which is a pointer to a struct,
+// or a pointer to a slice of structs or pointers to structs.
+//
+// Each relation runs one query on runner, which can be a Session or a Tx,
+// for all parents, and nested relations run one query for all children.
+// Queries are split if the keys exceed the IN list or parameter limit
+// of the dialect.
+func Preload(ctx context.Context, runner SessionRunner, parents interface{}, rel ...Relation) error {
+	v := reflect.ValueOf(parents)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrInvalidPointer
+	}
+	elem, ok := structElems(v.Elem(), nil)
+	if !ok {
+		return ErrInvalidPointer
+	}
+	s := newTagStore()
+	for _, r := range rel {
+		err := preload(ctx, runner, s, elem, r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structElems appends the structs in v to elem.
+func structElems(v reflect.Value, elem []reflect.Value) ([]reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		return append(elem, v), true
+	case reflect.Ptr:
+		if v.IsNil() {
+			return elem, v.Type().Elem().Kind() == reflect.Struct
+		}
+		return structElems(v.Elem(), elem)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			var ok bool
+			elem, ok = structElems(v.Index(i), elem)
+			if !ok {
+				return nil, false
+			}
+		}
+		return elem, true
+	}
+	return nil, false
+}
+
+func preload(ctx context.Context, runner SessionRunner, s *tagStore, parent []reflect.Value, r Relation) error {
+	if len(parent) == 0 {
+		return nil
+	}
+	field, ok := parent[0].Type().FieldByName(r.Field)
+	if !ok {
+		return ErrInvalidRelation
+	}
+	r, err := r.withTag(field.Tag.Get("dbr"))
+	if err != nil {
+		return err
+	}
+	if r.Key == "" {
+		r.Key = "id"
+	}
+	if r.Table == "" || r.ForeignKey == "" {
+		return ErrInvalidRelation
+	}
+
+	var parentColumn, childColumn string
+	childType := field.Type
+	switch r.Kind {
+	case HasMany:
+		if field.Type.Kind() != reflect.Slice {
+			return ErrInvalidRelation
+		}
+		parentColumn, childColumn = r.Key, r.ForeignKey
+		childType = field.Type.Elem()
+	case BelongsTo:
+		parentColumn, childColumn = r.ForeignKey, r.Key
+	default:
+		return ErrInvalidRelation
+	}
+
+	parentKey := make([]interface{}, len(parent))
+	var key []interface{}
+	seen := make(map[interface{}]bool)
+	for i, p := range parent {
+		k, ok := s.columnKey(p, parentColumn)
+		if !ok {
+			continue
+		}
+		parentKey[i] = k
+		if !seen[k] {
+			seen[k] = true
+			key = append(key, k)
+		}
+	}
+
+	child := reflect.New(reflect.SliceOf(childType))
+	if len(key) > 0 {
+		err := r.load(ctx, runner, childColumn, key, child)
+		if err != nil {
+			return err
+		}
+		if len(r.Preload) > 0 {
+			err := Preload(ctx, runner, child.Interface(), r.Preload...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	child = child.Elem()
+
+	// children by key
+	group := make(map[interface{}]reflect.Value)
+	for i := 0; i < child.Len(); i++ {
+		c := child.Index(i)
+		k, ok := s.columnKey(c, childColumn)
+		if !ok {
+			continue
+		}
+		switch r.Kind {
+		case HasMany:
+			l, ok := group[k]
+			if !ok {
+				l = reflect.MakeSlice(field.Type, 0, 1)
+			}
+			group[k] = reflect.Append(l, c)
+		case BelongsTo:
+			if _, ok := group[k]; !ok {
+				group[k] = c
+			}
+		}
+	}
+
+	for i, p := range parent {
+		f, err := p.FieldByIndexErr(field.Index)
+		if err != nil {
+			// nil embedded struct
+			continue
+		}
+		c, ok := group[parentKey[i]]
+		switch {
+		case ok && r.Kind == HasMany:
+			// parents with the same key do not share the slice.
+			f.Set(reflect.AppendSlice(reflect.MakeSlice(field.Type, 0, c.Len()), c))
+		case ok:
+			f.Set(c)
+		case r.Kind == HasMany:
+			// preloaded without children
+			f.Set(reflect.MakeSlice(field.Type, 0, 0))
+		}
+	}
+	return nil
+}
+
+// load appends the children whose childColumn is one of key to child,
+// which is a pointer to a slice. Keys are split into as many queries as needed
+// to stay within the IN list limit of the dialect, and its parameter limit
+// if values are bound.
+func (r Relation) load(ctx context.Context, runner SessionRunner, childColumn string, key []interface{}, child reflect.Value) error {
+	column := []interface{}{"*"}
+	if len(r.Column) > 0 {
+		column = column[:0]
+		hasChildColumn := false
+		for _, col := range r.Column {
+			column = append(column, col)
+			hasChildColumn = hasChildColumn || col == childColumn
+		}
+		if !hasChildColumn {
+			column = append(column, childColumn)
+		}
+	}
+
+	n := len(key)
+	base := runner.Select()
+	d := base.Dialect
+	if max := maxInList(d); max > 0 && max < n {
+		n = max
+	}
+	// keys are parameters only if values are bound.
+	if max := maxParams(d); max > 0 && (usePreparedStmt(base.Runner) || useBindParams(base.Runner)) {
+		if len(r.Where) > 0 {
+			_, value, err := ToSQL(And(r.Where...), d)
+			if err != nil {
+				return err
+			}
+			max -= len(value)
+		}
+		if max <= 0 {
+			return ErrPlaceholderCount
+		}
+		if max < n {
+			n = max
+		}
+	}
+
+	for len(key) > 0 {
+		chunk := key
+		if len(chunk) > n {
+			chunk = chunk[:n]
+		}
+		key = key[len(chunk):]
+
+		stmt := runner.Select(column...).From(r.Table).Where(Eq(childColumn, chunk))
+		for _, cond := range r.Where {
+			stmt.Where(cond)
+		}
+		for _, col := range r.OrderBy {
+			stmt.OrderBy(col)
+		}
+		rows := reflect.New(child.Type().Elem())
+		_, err := stmt.LoadContext(ctx, rows.Interface())
+		if err != nil {
+			return err
+		}
+		child.Elem().Set(reflect.AppendSlice(child.Elem(), rows.Elem()))
+	}
+	return nil
+}
+
+// withTag fills zero fields of r from tag.
+func (r Relation) withTag(tag string) (Relation, error) {
+	if tag == "" {
+		return r, nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "has_many":
+			if r.Kind == 0 {
+				r.Kind = HasMany
+			}
+		case "belongs_to":
+			if r.Kind == 0 {
+				r.Kind = BelongsTo
+			}
+		case "table":
+			if r.Table == "" {
+				r.Table = v
+			}
+		case "foreign_key":
+			if r.ForeignKey == "" {
+				r.ForeignKey = v
+			}
+		case "key":
+			if r.Key == "" {
+				r.Key = v
+			}
+		default:
+			return r, ErrInvalidRelation
+		}
+	}
+	return r, nil
+}
+
+// columnKey returns the value of the field that is mapped to column in struct v,
+// converted so that values from different Go types can be compared.
+func (s *tagStore) columnKey(v reflect.Value, column string) (interface{}, bool) {
+	ret := make([]interface{}, 1)
+	s.findValueByName(v, []string{column}, ret, false)
+	if ret[0] == nil {
+		return nil, false
+	}
+	f := ret[0].(reflect.Value)
+	if f.Type().Implements(typeValuer) {
+		if f.Kind() == reflect.Ptr && f.IsNil() {
+			return nil, false
+		}
+		value, err := f.Interface().(driver.Valuer).Value()
+		if err != nil || value == nil {
+			return nil, false
+		}
+		f = reflect.ValueOf(value)
+	}
+	for f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return nil, false
+		}
+		f = f.Elem()
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(f.Uint()), true
+	case reflect.String:
+		return f.String(), true
+	case reflect.Slice:
+		if f.Type().Elem().Kind() == reflect.Uint8 {
+			return string(f.Bytes()), true
+		}
+		return nil, false
+	}
+	if !f.Type().Comparable() {
+		return nil, false
+	}
+	return f.Interface(), true
+}
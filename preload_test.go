@@ -0,0 +1,178 @@
+package dbr
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type preloadUser struct {
+	ID     int64
+	Name   string
+	Orders []preloadOrder `db:"-" dbr:"has_many,table=orders,foreign_key=user_id"`
+}
+
+type preloadOrder struct {
+	ID     int64
+	UserID NullInt64
+	User   *preloadUser   `db:"-" dbr:"belongs_to,table=users,foreign_key=user_id"`
+	Items  []*preloadItem `db:"-"`
+}
+
+type preloadItem struct {
+	ID      int64
+	OrderID int
+	Name    string
+}
+
+func TestPreload(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+	ctx := context.Background()
+
+	users := []preloadUser{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 1, Name: "c"}}
+	mock.ExpectQuery("SELECT * FROM orders WHERE (`user_id` IN (1,2)) ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(10, 1).AddRow(11, 1))
+	mock.ExpectQuery("SELECT id, name, order_id FROM items WHERE (`order_id` IN (10,11)) AND (name <> '')").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "order_id"}).AddRow(100, "x", 11))
+	err = Preload(ctx, sess, &users, Relation{
+		Field:   "Orders",
+		OrderBy: []string{"id"},
+		Preload: []Relation{{
+			Field:      "Items",
+			Kind:       HasMany,
+			Table:      "items",
+			ForeignKey: "order_id",
+			Column:     []string{"id", "name"},
+			Where:      []Builder{Expr("name <> ''")},
+		}},
+	})
+	require.NoError(t, err)
+
+	item := &preloadItem{ID: 100, OrderID: 11, Name: "x"}
+	orders := []preloadOrder{
+		{ID: 10, UserID: NewNullInt64(1), Items: []*preloadItem{}},
+		{ID: 11, UserID: NewNullInt64(1), Items: []*preloadItem{item}},
+	}
+	require.Equal(t, []preloadUser{
+		{ID: 1, Name: "a", Orders: orders},
+		{ID: 2, Name: "b", Orders: []preloadOrder{}},
+		{ID: 1, Name: "c", Orders: orders},
+	}, users)
+	// parents with the same key have their own slices
+	require.NotSame(t, &users[0].Orders[0], &users[2].Orders[0])
+
+	// belongs to, in a transaction
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT * FROM users WHERE (`id` IN (1,2))").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	mock.ExpectCommit()
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	order := []*preloadOrder{
+		{ID: 10, UserID: NewNullInt64(1)},
+		{ID: 11, UserID: NewNullInt64(2)},
+		{ID: 12},
+	}
+	err = Preload(ctx, tx, &order, Relation{Field: "User"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, &preloadUser{ID: 1, Name: "a"}, order[0].User)
+	require.Equal(t, &preloadUser{ID: 2, Name: "b"}, order[1].User)
+	require.Nil(t, order[2].User)
+
+	// single parent without children
+	mock.ExpectQuery("SELECT * FROM orders WHERE (`user_id` IN (3))").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}))
+	one := preloadUser{ID: 3}
+	err = Preload(ctx, sess, &one, Relation{Field: "Orders"})
+	require.NoError(t, err)
+	require.NotNil(t, one.Orders)
+	require.Empty(t, one.Orders)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreloadInvalid(t *testing.T) {
+	sess := NewConnection(nil, dialect.MySQL, nil).NewSession(nil)
+	ctx := context.Background()
+	users := []preloadUser{{ID: 1}}
+
+	require.Equal(t, ErrInvalidPointer, Preload(ctx, sess, users, Relation{Field: "Orders"}))
+	require.Equal(t, ErrInvalidPointer, Preload(ctx, sess, &[]int{1}, Relation{Field: "Orders"}))
+	require.Equal(t, ErrInvalidRelation, Preload(ctx, sess, &users, Relation{Field: "Missing"}))
+	require.Equal(t, ErrInvalidRelation, Preload(ctx, sess, &users, Relation{Field: "Name", Kind: HasMany, Table: "t", ForeignKey: "k"}))
+
+	var order []preloadOrder
+	require.Equal(t, ErrInvalidRelation, Preload(ctx, sess, &[]preloadOrder{{ID: 1}}, Relation{Field: "Items"}))
+	require.NoError(t, Preload(ctx, sess, &order, Relation{Field: "Items"}))
+}
+
+// maxParamsDialect limits the number of bind parameters of Dialect.
+type maxParamsDialect struct {
+	Dialect
+	max int
+}
+
+func (d maxParamsDialect) MaxParams() int {
+	return d.max
+}
+
+func TestPreloadMaxParams(t *testing.T) {
+	sess, mock := newMockSession(t, maxParamsDialect{Dialect: dialect.MySQL, max: 3}, nil)
+	sess.BindParams = true
+
+	// one parameter is used by the condition, so two keys are in each query.
+	users := []preloadUser{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	mock.ExpectQuery("SELECT * FROM orders WHERE (`user_id` IN (?,?)) AND (active = ?)").
+		WithArgs(1, 2, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(10, 1).AddRow(11, 2))
+	mock.ExpectQuery("SELECT * FROM orders WHERE (`user_id` IN (?,?)) AND (active = ?)").
+		WithArgs(3, 4, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(12, 4))
+	mock.ExpectQuery("SELECT * FROM orders WHERE (`user_id` IN (?)) AND (active = ?)").
+		WithArgs(5, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(13, 5))
+	err := Preload(context.Background(), sess, &users, Relation{
+		Field: "Orders",
+		Where: []Builder{Expr("active = ?", true)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	var id []int64
+	for _, u := range users {
+		for _, o := range u.Orders {
+			id = append(id, o.ID)
+		}
+	}
+	require.Equal(t, []int64{10, 11, 12, 13}, id)
+	require.Empty(t, users[2].Orders)
+}
+
+func TestPreloadMaxInList(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.Oracle, nil)
+
+	// oracle allows 1000 values in IN, even if they are not parameters.
+	users := make([]preloadUser, 1001)
+	var key []string
+	for i := range users {
+		users[i].ID = int64(i + 1)
+		key = append(key, strconv.Itoa(i+1))
+	}
+	mock.ExpectQuery(`SELECT * FROM orders WHERE ("user_id" IN (` + strings.Join(key[:1000], ",") + `))`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(10, 1))
+	mock.ExpectQuery(`SELECT * FROM orders WHERE ("user_id" IN (1001))`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(11, 1001))
+	err := Preload(context.Background(), sess, &users, Relation{Field: "Orders"})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, users[0].Orders, 1)
+	require.Len(t, users[1000].Orders, 1)
+}
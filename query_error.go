@@ -0,0 +1,88 @@
+package dbr
+
+import "time"
+
+// RedactQueryErrors controls whether QueryError.SQL has placeholders
+// instead of the values of the statement, so that errors can be logged
+// without leaking data. It is true by default.
+var RedactQueryErrors = true
+
+// QueryError is an error from running a statement.
+// The cause is returned by errors.Unwrap.
+type QueryError struct {
+	// Op is the operation that failed: exec, select, load or scan.
+	Op string
+	// SQL is the statement. See RedactQueryErrors.
+	SQL string
+	// Stmt is the kind of statement, like select or insert.
+	// It is empty if the statement is not built by dbr.
+	Stmt string
+	// Table is the table of the statement, if it is known.
+	Table    string
+	Duration time.Duration
+	Err      error
+}
+
+func (e *QueryError) Error() string {
+	s := "dbr: " + e.Op
+	if e.Stmt != "" {
+		s += " " + e.Stmt
+	}
+	if e.Table != "" {
+		s += " " + e.Table
+	}
+	return s + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of e.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// newQueryError wraps err with the context of builder.
+// query is built with values unless bound is true.
+func newQueryError(op string, builder Builder, d Dialect, query string, bound bool, start time.Time, err error) error {
+	e := &QueryError{
+		Op:       op,
+		SQL:      query,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if RedactQueryErrors && !bound {
+		e.SQL = redactSQL(builder, d)
+	}
+
+	switch b := builder.(type) {
+	case *SelectStmt:
+		e.Stmt = "select"
+		e.Table, _ = b.Table.(string)
+	case *InsertStmt:
+		e.Stmt = "insert"
+		e.Table = b.Table
+	case *UpdateStmt:
+		e.Stmt = "update"
+		e.Table = b.Table
+	case *DeleteStmt:
+		e.Stmt = "delete"
+		e.Table = b.Table
+	case *BulkUpdateStmt:
+		e.Stmt = "update"
+		e.Table = b.Table
+	}
+	return e
+}
+
+// redactSQL builds builder with placeholders for all values.
+func redactSQL(builder Builder, d Dialect) string {
+	i := interpolator{
+		Buffer:       NewBuffer(),
+		Dialect:      d,
+		IgnoreBinary: true,
+		Bind:         true,
+	}
+	err := i.encodePlaceholder(builder, true)
+	if err != nil {
+		return ""
+	}
+	return i.String()
+}
@@ -0,0 +1,71 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+
+	cause := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'email'"}
+	mock.ExpectExec("INSERT INTO `dbr_people`").WillReturnError(cause)
+	_, err = sess.InsertInto("dbr_people").Pair("email", "a@b.c").Exec()
+
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	require.Equal(t, "exec", queryErr.Op)
+	require.Equal(t, "INSERT INTO `dbr_people` (`email`) VALUES (?)", queryErr.SQL)
+	require.Equal(t, "insert", queryErr.Stmt)
+	require.Equal(t, "dbr_people", queryErr.Table)
+	require.True(t, queryErr.Duration > 0)
+	require.Equal(t, "dbr: exec insert dbr_people: "+cause.Error(), err.Error())
+	require.ErrorIs(t, err, cause)
+	require.ErrorIs(t, err, ErrUniqueViolation)
+
+	// not redacted
+	RedactQueryErrors = false
+	defer func() {
+		RedactQueryErrors = true
+	}()
+	mock.ExpectQuery("SELECT").WillReturnError(cause)
+	var id int
+	err = sess.Select("id").From("dbr_people").Where(Eq("email", "a@b.c")).LoadOne(&id)
+	require.True(t, errors.As(err, &queryErr))
+	require.Equal(t, "select", queryErr.Op)
+	require.Equal(t, "SELECT id FROM dbr_people WHERE (`email` = 'a@b.c')", queryErr.SQL)
+	RedactQueryErrors = true
+
+	// load
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("a"))
+	err = sess.Select("id").From("dbr_people").Where(Eq("email", "a@b.c")).LoadOne(&id)
+	require.True(t, errors.As(err, &queryErr))
+	require.Equal(t, "load", queryErr.Op)
+	require.Equal(t, "SELECT id FROM dbr_people WHERE (`email` = ?)", queryErr.SQL)
+
+	// scan
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("a"))
+	iter, err := sess.SelectBySql("SELECT id FROM dbr_people").Iterate()
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	err = iter.Scan(&id)
+	require.True(t, errors.As(err, &queryErr))
+	require.Equal(t, "scan", queryErr.Op)
+	require.Equal(t, "select", queryErr.Stmt)
+	require.Equal(t, "", queryErr.Table)
+	require.NoError(t, iter.Close())
+
+	// not wrapped
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = sess.Select("id").From("dbr_people").LoadOne(&id)
+	require.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,189 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Replica is a read replica of the primary database.
+type Replica struct {
+	// Name identifies the replica in events.
+	Name string
+	DB   *sql.DB
+	// Weight is the share of reads that the replica receives
+	// relative to the other replicas. Zero means 1.
+	Weight int
+}
+
+type replica struct {
+	Replica
+	down atomic.Bool
+}
+
+// ReplicaPool routes reads of sessions to read replicas.
+//
+// Only SelectStmt that is created from a Session reads from a replica.
+// Writes, and every statement in a Tx, use the primary Connection.
+// Reads use the primary as well if ctx is from WithPrimary, if the statement
+// locks rows with a clause like FOR UPDATE, or if no replica is healthy.
+//
+// Reads are spread across replicas in weighted round-robin order.
+// Prepared statement mode is only used on the primary.
+type ReplicaPool struct {
+	log      EventReceiver
+	replicas []*replica
+	weight   []int
+	next     atomic.Uint64
+}
+
+// NewReplicaPool creates a ReplicaPool. All replicas are healthy until Check fails.
+// log can be nil to ignore logging.
+func NewReplicaPool(log EventReceiver, replicas ...Replica) *ReplicaPool {
+	if log == nil {
+		log = nullReceiver
+	}
+	p := &ReplicaPool{log: log}
+	for i, r := range replicas {
+		if r.Name == "" {
+			r.Name = strconv.Itoa(i)
+		}
+		if r.Weight <= 0 {
+			r.Weight = 1
+		}
+		p.replicas = append(p.replicas, &replica{Replica: r})
+		for j := 0; j < r.Weight; j++ {
+			p.weight = append(p.weight, i)
+		}
+	}
+	return p
+}
+
+// Check pings every replica. A replica that fails receives no reads
+// until it responds again.
+func (p *ReplicaPool) Check(ctx context.Context) {
+	for _, r := range p.replicas {
+		err := r.DB.PingContext(ctx)
+		if err != nil {
+			if !r.down.Swap(true) {
+				p.log.EventErrKv("dbr.replica.down", err, kvs{
+					"replica": r.Name,
+				})
+			}
+			continue
+		}
+		if r.down.Swap(false) {
+			p.log.EventKv("dbr.replica.up", kvs{
+				"replica": r.Name,
+			})
+		}
+	}
+}
+
+// Watch calls Check every interval until ctx is done.
+func (p *ReplicaPool) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pick returns the next healthy replica, or nil.
+func (p *ReplicaPool) pick() *replica {
+	if len(p.weight) == 0 {
+		return nil
+	}
+	n := p.weight[(p.next.Add(1)-1)%uint64(len(p.weight))]
+	for i := range p.replicas {
+		r := p.replicas[(n+i)%len(p.replicas)]
+		if !r.down.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context in which sessions read from the primary,
+// so that reads see the writes that were just made.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// readRouter is implemented by runners that can send reads elsewhere.
+type readRouter interface {
+	readRunner(ctx context.Context, locking bool) Runner
+}
+
+func (sess *Session) readRunner(ctx context.Context, locking bool) Runner {
+	if sess.Replicas == nil {
+		return sess
+	}
+	if locking {
+		// rows can only be locked on the primary.
+		sess.EventKv("dbr.route.primary", kvs{
+			"reason": "lock",
+		})
+		return sess
+	}
+	if usePrimary(ctx) {
+		sess.EventKv("dbr.route.primary", kvs{
+			"reason": "context",
+		})
+		return sess
+	}
+	r := sess.Replicas.pick()
+	if r == nil {
+		sess.EventKv("dbr.route.primary", kvs{
+			"reason": "no healthy replica",
+		})
+		return sess
+	}
+	sess.EventKv("dbr.route.replica", kvs{
+		"replica": r.Name,
+	})
+	return &replicaRunner{
+		DB:      r.DB,
+		timeout: sess.Timeout,
+		// statements are not prepared on replicas,
+		// but values are bound like in prepared statement mode.
+		bind: sess.BindParams || sess.StmtCache != nil,
+	}
+}
+
+type replicaRunner struct {
+	*sql.DB
+	timeout time.Duration
+	bind    bool
+}
+
+func (r *replicaRunner) GetTimeout() time.Duration {
+	return r.timeout
+}
+
+func (r *replicaRunner) bindParams() bool {
+	return r.bind
+}
+
+// readRunner returns the runner that reads with ctx.
+// locking is true if the read locks rows.
+func readRunner(ctx context.Context, runner Runner, locking bool) Runner {
+	if r, ok := runner.(readRouter); ok {
+		return r.readRunner(ctx, locking)
+	}
+	return runner
+}
@@ -0,0 +1,151 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaPool(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	replica1, replica1Mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	replica2, replica2Mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+
+	log := &testEventReceiver{}
+	conn := &Connection{
+		DB:            primary,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.MySQL,
+		Replicas: NewReplicaPool(log,
+			Replica{Name: "r1", DB: replica1, Weight: 2},
+			Replica{Name: "r2", DB: replica2},
+		),
+	}
+	sess := conn.NewSession(log)
+
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id"}).AddRow(1)
+	}
+	readID := func(ctx context.Context) {
+		var id int64
+		err := sess.Select("id").From("dbr_people").LoadOneContext(ctx, &id)
+		require.NoError(t, err)
+	}
+
+	// weighted round-robin
+	replica1Mock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	replica1Mock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	replica2Mock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	for i := 0; i < 3; i++ {
+		readID(context.Background())
+	}
+	require.Equal(t, []string{"dbr.route.replica", "dbr.route.replica", "dbr.route.replica"}, log.events)
+	log.events = nil
+
+	// writes, reads with WithPrimary, and transactions use the primary
+	primaryMock.ExpectExec("UPDATE `dbr_people` SET `name` = 'a'").WillReturnResult(sqlmock.NewResult(0, 1))
+	primaryMock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	primaryMock.ExpectCommit()
+
+	_, err = sess.Update("dbr_people").Set("name", "a").Exec()
+	require.NoError(t, err)
+	readID(WithPrimary(context.Background()))
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	var id int64
+	require.NoError(t, tx.Select("id").From("dbr_people").LoadOne(&id))
+	require.NoError(t, tx.Commit())
+	require.Equal(t, []string{"dbr.route.primary"}, log.events)
+	log.events = nil
+
+	// locking reads use the primary
+	primaryMock.ExpectQuery("SELECT id FROM dbr_people WHERE \\(id = 1\\) FOR UPDATE").WillReturnRows(rows())
+	primaryMock.ExpectQuery("SELECT id FROM dbr_people WHERE id = 1 FOR SHARE").WillReturnRows(rows())
+	require.NoError(t, sess.Select("id").From("dbr_people").Where("id = ?", 1).Suffix("FOR UPDATE").LoadOne(&id))
+	require.NoError(t, sess.SelectBySql("SELECT id FROM dbr_people WHERE id = ? FOR SHARE", 1).LoadOne(&id))
+	require.Equal(t, []string{"dbr.route.primary", "dbr.route.primary"}, log.events)
+	log.events = nil
+
+	// a replica that fails the health check receives no reads
+	replica1Mock.ExpectPing().WillReturnError(errors.New("down"))
+	replica2Mock.ExpectPing()
+	conn.Replicas.Check(context.Background())
+	require.Equal(t, []string{"dbr.replica.down"}, log.events)
+
+	replica2Mock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	replica2Mock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	readID(context.Background())
+	readID(context.Background())
+
+	// no healthy replica
+	log.events = nil
+	replica1Mock.ExpectPing().WillReturnError(errors.New("down"))
+	replica2Mock.ExpectPing().WillReturnError(errors.New("down"))
+	conn.Replicas.Check(context.Background())
+	primaryMock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(rows())
+	readID(context.Background())
+	require.Equal(t, []string{"dbr.replica.down", "dbr.route.primary"}, log.events)
+
+	// replicas come back
+	replica1Mock.ExpectPing()
+	replica2Mock.ExpectPing()
+	log.events = nil
+	conn.Replicas.Check(context.Background())
+	require.Equal(t, []string{"dbr.replica.up", "dbr.replica.up"}, log.events)
+
+	require.NoError(t, primaryMock.ExpectationsWereMet())
+	require.NoError(t, replica1Mock.ExpectationsWereMet())
+	require.NoError(t, replica2Mock.ExpectationsWereMet())
+}
+
+func TestSelectStmtLocking(t *testing.T) {
+	for _, test := range []struct {
+		builder *SelectStmt
+		want    bool
+	}{
+		{builder: Select("id").From("t").Suffix("FOR UPDATE SKIP LOCKED"), want: true},
+		{builder: Select("id").From("t").Suffix("for no key update"), want: true},
+		{builder: Select("id").From("t").Suffix("LOCK IN SHARE MODE"), want: true},
+		{builder: SelectBySql("SELECT id FROM t FOR KEY SHARE"), want: true},
+		{builder: Select("id").From("t"), want: false},
+		{builder: SelectBySql("SELECT before_update FROM t"), want: false},
+	} {
+		require.Equal(t, test.want, test.builder.locking())
+	}
+}
+
+func TestReplicaPoolBindParams(t *testing.T) {
+	primary, _, err := sqlmock.New()
+	require.NoError(t, err)
+	replica, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            primary,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.PostgreSQL,
+		Replicas:      NewReplicaPool(nil, Replica{Name: "r1", DB: replica}),
+	}
+	conn.StmtCache = NewStmtCache(primary, 10, nil)
+	sess := conn.NewSession(nil)
+
+	// prepared statement mode binds values on replicas as well
+	replicaMock.ExpectQuery(`SELECT id FROM dbr_people WHERE \(id = \$1\)`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var id int64
+	require.NoError(t, sess.Select("id").From("dbr_people").Where("id = ?", 1).LoadOne(&id))
+	require.Equal(t, int64(1), id)
+
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+}
@@ -0,0 +1,198 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// Savepoint marks the current state of the transaction with name,
+// so that RollbackToSavepoint can undo the changes after it.
+//
+// name must be an identifier of letters, digits and underscores.
+func (tx *Tx) Savepoint(name string) error {
+	return tx.SavepointContext(context.Background(), name)
+}
+
+// SavepointContext is like Savepoint with a context.
+func (tx *Tx) SavepointContext(ctx context.Context, name string) error {
+	query := "SAVEPOINT "
+	if savepointStyle(tx.Dialect) == dialect.SaveTransaction {
+		query = "SAVE TRANSACTION "
+	}
+	return tx.execSavepoint(ctx, "dbr.savepoint", query, name)
+}
+
+// RollbackToSavepoint undoes the changes after the savepoint with name.
+// The savepoint is kept.
+func (tx *Tx) RollbackToSavepoint(name string) error {
+	return tx.RollbackToSavepointContext(context.Background(), name)
+}
+
+// RollbackToSavepointContext is like RollbackToSavepoint with a context.
+func (tx *Tx) RollbackToSavepointContext(ctx context.Context, name string) error {
+	query := "ROLLBACK TO SAVEPOINT "
+	if savepointStyle(tx.Dialect) == dialect.SaveTransaction {
+		query = "ROLLBACK TRANSACTION "
+	}
+	return tx.execSavepoint(ctx, "dbr.rollback_to_savepoint", query, name)
+}
+
+// ReleaseSavepoint removes the savepoint with name, and keeps the changes after it.
+func (tx *Tx) ReleaseSavepoint(name string) error {
+	return tx.ReleaseSavepointContext(context.Background(), name)
+}
+
+// ReleaseSavepointContext is like ReleaseSavepoint with a context.
+func (tx *Tx) ReleaseSavepointContext(ctx context.Context, name string) error {
+	if savepointStyle(tx.Dialect) != dialect.Savepoint {
+		// savepoints are released when the transaction ends.
+		if !savepointName.MatchString(name) {
+			return ErrInvalidSavepoint
+		}
+		return nil
+	}
+	return tx.execSavepoint(ctx, "dbr.release_savepoint", "RELEASE SAVEPOINT ", name)
+}
+
+// savepointName is the names that are safe to use without quotes.
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (tx *Tx) execSavepoint(ctx context.Context, eventName, query, name string) error {
+	if !savepointName.MatchString(name) {
+		return ErrInvalidSavepoint
+	}
+	_, err := tx.Tx.ExecContext(ctx, query+name)
+	if err != nil {
+		return tx.EventErrKv(eventName+".error", err, kvs{
+			"savepoint": name,
+		})
+	}
+	tx.EventKv(eventName, kvs{
+		"savepoint": name,
+	})
+	return nil
+}
+
+// Begin starts a nested transaction with a savepoint.
+func (tx *Tx) Begin() (*Tx, error) {
+	return tx.BeginTx(context.Background(), nil)
+}
+
+// BeginTx starts a nested transaction with a savepoint.
+//
+// Commit of the nested transaction releases the savepoint, and its changes
+// are committed with the outer transaction. Rollback only undoes
+// the changes of the nested transaction. The outer transaction cannot
+// be committed until its nested transactions end, and rolling it back
+// also rolls back the nested transactions that are still open.
+//
+// opts must be nil, because a nested transaction keeps the options
+// of the outer transaction. Otherwise it returns ErrNotSupported.
+func (tx *Tx) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if opts != nil {
+		return nil, ErrNotSupported
+	}
+	root := tx
+	for root.parent != nil {
+		root = root.parent
+	}
+	name := "dbr_savepoint_" + strconv.FormatInt(root.savepoints.Add(1), 10)
+
+	err := tx.SavepointContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	nested := &Tx{
+		EventReceiver: tx.EventReceiver,
+		Dialect:       tx.Dialect,
+		Tx:            tx.Tx,
+		Timeout:       tx.Timeout,
+		StmtCache:     tx.StmtCache,
+		BindParams:    tx.BindParams,
+		parent:        tx,
+		savepoint:     name,
+	}
+	tx.nestedMu.Lock()
+	tx.nested = append(tx.nested, nested)
+	tx.nestedMu.Unlock()
+	return nested, nil
+}
+
+// Depth returns the number of transactions that tx is nested in.
+func (tx *Tx) Depth() int {
+	depth := 0
+	for p := tx.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+func (tx *Tx) commitSavepoint() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	if tx.hasOpenNested() {
+		return ErrNestedTxOpen
+	}
+	err := tx.ReleaseSavepoint(tx.savepoint)
+	if err != nil {
+		return err
+	}
+	tx.end()
+	tx.runHooks(true)
+	return nil
+}
+
+func (tx *Tx) rollbackSavepoint() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	err := tx.RollbackToSavepoint(tx.savepoint)
+	if err != nil {
+		return err
+	}
+	tx.rollbackNested()
+	tx.end()
+	tx.runHooks(false)
+	return tx.ReleaseSavepoint(tx.savepoint)
+}
+
+// end marks the nested transaction tx as done, and removes it
+// from the open nested transactions of its parent.
+func (tx *Tx) end() {
+	tx.done = true
+	p := tx.parent
+	p.nestedMu.Lock()
+	for i, nested := range p.nested {
+		if nested == tx {
+			p.nested = append(p.nested[:i], p.nested[i+1:]...)
+			break
+		}
+	}
+	p.nestedMu.Unlock()
+}
+
+// hasOpenNested reports whether tx has nested transactions that have not ended.
+func (tx *Tx) hasOpenNested() bool {
+	tx.nestedMu.Lock()
+	defer tx.nestedMu.Unlock()
+	return len(tx.nested) > 0
+}
+
+// rollbackNested ends the open nested transactions of tx, which are rolled back
+// with tx, and runs their hooks from the innermost one.
+func (tx *Tx) rollbackNested() {
+	tx.nestedMu.Lock()
+	nested := tx.nested
+	tx.nested = nil
+	tx.nestedMu.Unlock()
+	for i := len(nested) - 1; i >= 0; i-- {
+		nested[i].rollbackNested()
+		nested[i].done = true
+		nested[i].runHooks(false)
+	}
+}
@@ -0,0 +1,154 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavepoint(t *testing.T) {
+	for _, test := range []struct {
+		d        Dialect
+		save     string
+		rollback string
+		release  string
+	}{
+		{
+			d:        dialect.PostgreSQL,
+			save:     "SAVEPOINT sp",
+			rollback: "ROLLBACK TO SAVEPOINT sp",
+			release:  "RELEASE SAVEPOINT sp",
+		},
+		{
+			d:        dialect.MySQL,
+			save:     "SAVEPOINT sp",
+			rollback: "ROLLBACK TO SAVEPOINT sp",
+			release:  "RELEASE SAVEPOINT sp",
+		},
+		{
+			d:        dialect.MSSQL,
+			save:     "SAVE TRANSACTION sp",
+			rollback: "ROLLBACK TRANSACTION sp",
+		},
+		{
+			d:        dialect.Oracle,
+			save:     "SAVEPOINT sp",
+			rollback: "ROLLBACK TO SAVEPOINT sp",
+		},
+	} {
+		sess, mock := newMockSession(t, test.d, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(test.save).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(test.rollback).WillReturnResult(sqlmock.NewResult(0, 0))
+		if test.release != "" {
+			mock.ExpectExec(test.release).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectCommit()
+
+		tx, err := sess.Begin()
+		require.NoError(t, err)
+		require.NoError(t, tx.Savepoint("sp"))
+		require.NoError(t, tx.RollbackToSavepoint("sp"))
+		require.NoError(t, tx.ReleaseSavepoint("sp"))
+		require.Equal(t, ErrInvalidSavepoint, tx.Savepoint("sp; DROP TABLE dbr_people"))
+		require.Equal(t, ErrInvalidSavepoint, tx.RollbackToSavepoint(`"sp"`))
+		require.Equal(t, ErrInvalidSavepoint, tx.ReleaseSavepoint("1sp"))
+		require.NoError(t, tx.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestNestedTx(t *testing.T) {
+	log := &txEventReceiver{}
+	sess, mock := newMockSession(t, dialect.PostgreSQL, log)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT dbr_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "dbr_people" ("name") VALUES ('a')`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "dbr_people" ("name") VALUES ('b')`).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT dbr_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	defer tx.RollbackUnlessCommitted()
+
+	child, err := tx.Begin()
+	require.NoError(t, err)
+	require.Equal(t, 1, child.Depth())
+	_, err = child.InsertInto("dbr_people").Pair("name", "a").Exec()
+	require.NoError(t, err)
+
+	grandchild, err := child.Begin()
+	require.NoError(t, err)
+	require.Equal(t, 2, grandchild.Depth())
+	_, err = grandchild.InsertInto("dbr_people").Pair("name", "b").Exec()
+	require.NoError(t, err)
+	grandchild.RollbackUnlessCommitted()
+	require.Equal(t, sql.ErrTxDone, grandchild.Rollback())
+
+	require.NoError(t, child.Commit())
+	require.Equal(t, sql.ErrTxDone, child.Commit())
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, []string{
+		"dbr.begin",
+		"dbr.savepoint",
+		"dbr.savepoint",
+		"dbr.rollback_to_savepoint",
+		"dbr.release_savepoint",
+		"dbr.release_savepoint",
+		"dbr.commit",
+	}, log.events)
+}
+
+func TestNestedTxOpen(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL, nil)
+
+	var calls []string
+	hook := func(name string) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT dbr_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	var beginner TxBeginner = sess
+	tx, err := beginner.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	tx.OnRollback(hook("outer"))
+
+	beginner = tx
+	_, err = beginner.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	require.Equal(t, ErrNotSupported, err)
+	child, err := beginner.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	child.OnRollback(hook("child"))
+	grandchild, err := child.Begin()
+	require.NoError(t, err)
+	grandchild.OnRollback(hook("grandchild"))
+
+	// open nested transactions are not committed with their parent
+	require.Equal(t, ErrNestedTxOpen, child.Commit())
+	require.Equal(t, ErrNestedTxOpen, tx.Commit())
+
+	// and are rolled back with it
+	require.NoError(t, tx.Rollback())
+	require.Equal(t, []string{"grandchild", "child", "outer"}, calls)
+	require.Equal(t, sql.ErrTxDone, child.Rollback())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,7 +3,9 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/gocraft/dbr/v2/dialect"
 )
@@ -31,13 +33,28 @@ type SelectStmt struct {
 	LimitCount  int64
 	OffsetCount int64
 
+	// clickhouse only
+	IsFinal       bool
+	SampleRatio   float64
+	PrewhereCond  []Builder
+	LimitByCount  int64
+	LimitByColumn []string
+	Setting       []Builder
+
 	comments Comments
 
 	indexHints []Builder
+
+	shard interface{}
 }
 
 type SelectBuilder = SelectStmt
 
+// ToSQL returns the query with placeholders of dialect d, and its arguments.
+func (b *SelectStmt) ToSQL(d Dialect) (string, []interface{}, error) {
+	return ToSQL(b, d)
+}
+
 func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 	if b.raw.Query != "" {
 		return b.raw.Build(d, buf)
@@ -90,6 +107,11 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 			}
 		}
 
+		err := b.buildFinalSample(d, buf)
+		if err != nil {
+			return err
+		}
+
 		if len(b.JoinTable) > 0 {
 			for _, join := range b.JoinTable {
 				err := join.Build(d, buf)
@@ -100,6 +122,13 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 		}
 	}
 
+	if len(b.PrewhereCond) > 0 {
+		err := b.buildPrewhere(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(b.WhereCond) > 0 {
 		buf.WriteString(" WHERE ")
 		err := And(b.WhereCond...).Build(d, buf)
@@ -142,9 +171,19 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 		}
 	}
 
-	if d == dialect.MSSQL {
+	if len(b.LimitByColumn) > 0 {
+		err := b.buildLimitBy(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch limitStyle(d) {
+	case dialect.OrderedOffsetFetch:
 		b.addMSSQLLimits(buf)
-	} else {
+	case dialect.OffsetFetch:
+		b.addOffsetFetchLimits(buf)
+	default:
 		if b.LimitCount >= 0 {
 			buf.WriteString(" LIMIT ")
 			buf.WriteString(strconv.FormatInt(b.LimitCount, 10))
@@ -156,6 +195,13 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 		}
 	}
 
+	if len(b.Setting) > 0 {
+		err := b.buildSettings(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(b.Suffixes) > 0 {
 		for _, suffix := range b.Suffixes {
 			buf.WriteString(" ")
@@ -205,6 +251,21 @@ func (b *SelectStmt) addMSSQLLimits(buf Buffer) {
 	}
 }
 
+// OFFSET / FETCH is supported since oracle 12c, and in standard SQL.
+func (b *SelectStmt) addOffsetFetchLimits(buf Buffer) {
+	if b.OffsetCount >= 0 {
+		buf.WriteString(" OFFSET ")
+		buf.WriteString(strconv.FormatInt(b.OffsetCount, 10))
+		buf.WriteString(" ROWS")
+	}
+
+	if b.LimitCount >= 0 {
+		buf.WriteString(" FETCH NEXT ")
+		buf.WriteString(strconv.FormatInt(b.LimitCount, 10))
+		buf.WriteString(" ROWS ONLY")
+	}
+}
+
 // Select creates a SelectStmt.
 func Select(column ...interface{}) *SelectStmt {
 	return &SelectStmt{
@@ -245,6 +306,9 @@ func SelectBySql(query string, value ...interface{}) *SelectStmt {
 }
 
 // SelectBySql creates a SelectStmt from raw query.
+//
+// Like Select, it reads from a replica if the session has replicas,
+// so use a context from WithPrimary to read the writes that were just made.
 func (sess *Session) SelectBySql(query string, value ...interface{}) *SelectStmt {
 	b := SelectBySql(query, value...)
 	b.Runner = sess
@@ -360,6 +424,13 @@ func (b *SelectStmt) Comment(comment string) *SelectStmt {
 	return b
 }
 
+// Shard sets the shard key of the statement, which is used instead of
+// the key in the context when it runs on a ShardedSession.
+func (b *SelectStmt) Shard(key interface{}) *SelectStmt {
+	b.shard = key
+	return b
+}
+
 // Join add inner-join.
 // on can be Builder or string.
 func (b *SelectStmt) Join(table, on interface{}, indexHints ...Builder) *SelectStmt {
@@ -399,12 +470,41 @@ func (b *SelectStmt) Rows() (*sql.Rows, error) {
 }
 
 func (b *SelectStmt) RowsContext(ctx context.Context) (*sql.Rows, error) {
-	_, rows, err := queryRows(ctx, b.Runner, b.EventReceiver, b, b.Dialect)
+	runner, log, err := b.readRunner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, rows, err := queryRows(ctx, runner, log, b, b.Dialect)
 	return rows, err
 }
 
+// readRunner returns the runner and the log that b reads with.
+func (b *SelectStmt) readRunner(ctx context.Context) (Runner, EventReceiver, error) {
+	runner, log, err := shardRunner(ctx, b.Runner, b.EventReceiver, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readRunner(ctx, runner, b.locking()), log, nil
+}
+
+// lockClause matches clauses that lock the rows that are read.
+var lockClause = regexp.MustCompile(`(?i)\bFOR\s+(NO\s+KEY\s+UPDATE|UPDATE|KEY\s+SHARE|SHARE)\b|\bLOCK\s+IN\s+SHARE\s+MODE\b`)
+
+// locking reports whether b locks rows, like with Suffix("FOR UPDATE").
+func (b *SelectStmt) locking() bool {
+	if lockClause.MatchString(b.raw.Query) {
+		return true
+	}
+	for _, suffix := range b.Suffixes {
+		if r, ok := suffix.(*raw); ok && lockClause.MatchString(r.Query) {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *SelectStmt) LoadOneContext(ctx context.Context, value interface{}) error {
-	count, err := query(ctx, b.Runner, b.EventReceiver, b, b.Dialect, value)
+	count, err := b.LoadContext(ctx, value)
 	if err != nil {
 		return err
 	}
@@ -423,7 +523,11 @@ func (b *SelectStmt) LoadOne(value interface{}) error {
 }
 
 func (b *SelectStmt) LoadContext(ctx context.Context, value interface{}) (int, error) {
-	return query(ctx, b.Runner, b.EventReceiver, b, b.Dialect, value)
+	runner, log, err := b.readRunner(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return query(ctx, runner, log, b, b.Dialect, value)
 }
 
 // Load loads multi-row SQL result into a slice of go variables.
@@ -440,7 +544,12 @@ func (b *SelectStmt) Iterate() (Iterator, error) {
 
 // IterateContext executes the query and returns the Iterator, or any error encountered.
 func (b *SelectStmt) IterateContext(ctx context.Context) (Iterator, error) {
-	_, rows, err := queryRows(ctx, b.Runner, b.EventReceiver, b, b.Dialect)
+	startTime := time.Now()
+	runner, log, err := b.readRunner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query, rows, err := queryRows(ctx, runner, log, b, b.Dialect)
 	if err != nil {
 		if rows != nil {
 			rows.Close()
@@ -457,6 +566,10 @@ func (b *SelectStmt) IterateContext(ctx context.Context) (Iterator, error) {
 	iterator := iteratorInternals{
 		rows:    rows,
 		columns: columns,
+		stmt:    b,
+		query:   query,
+		bound:   usePreparedStmt(runner) || useBindParams(runner),
+		start:   startTime,
 	}
 	return &iterator, err
 }
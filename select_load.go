@@ -0,0 +1,149 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// LoadAll runs b and loads all rows into a slice of T.
+// T is loaded like an element of a slice in Load.
+func LoadAll[T any](ctx context.Context, b *SelectStmt) ([]T, error) {
+	var v []T
+	var meta *recordMeta
+	err := loadEach(ctx, b, func(rows *sql.Rows, column []string) error {
+		if meta == nil {
+			var err error
+			meta, err = newRecordMeta(column, &v)
+			if err != nil {
+				return err
+			}
+		}
+		return meta.scan(rows, &v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// LoadSingle runs b and loads exactly one row into T.
+// It returns ErrNotFound if there is no row, and ErrMultipleRows
+// if there is more than one.
+func LoadSingle[T any](ctx context.Context, b *SelectStmt) (T, error) {
+	var zero T
+	v, err := LoadOptional[T](ctx, b)
+	if err != nil {
+		return zero, err
+	}
+	if v == nil {
+		return zero, ErrNotFound
+	}
+	return *v, nil
+}
+
+// LoadOptional runs b and loads at most one row into T.
+// It returns nil if there is no row, and ErrMultipleRows if there is more than one.
+func LoadOptional[T any](ctx context.Context, b *SelectStmt) (*T, error) {
+	var v []T
+	var meta *recordMeta
+	err := loadEach(ctx, b, func(rows *sql.Rows, column []string) error {
+		if meta == nil {
+			var err error
+			meta, err = newRecordMeta(column, &v)
+			if err != nil {
+				return err
+			}
+		}
+		if len(v) > 0 {
+			return ErrMultipleRows
+		}
+		return meta.scan(rows, &v)
+	})
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+	return &v[0], nil
+}
+
+// LoadColumn runs b and loads the first column of all rows into a slice of T.
+// Unlike LoadAll, T is always scanned as a single value, so it can be any type
+// that sql.Rows.Scan supports, like time.Time.
+func LoadColumn[T any](ctx context.Context, b *SelectStmt) ([]T, error) {
+	var v []T
+	var ptr []interface{}
+	err := loadEach(ctx, b, func(rows *sql.Rows, column []string) error {
+		if ptr == nil {
+			ptr = make([]interface{}, len(column))
+			for i := 1; i < len(ptr); i++ {
+				ptr[i] = dummyDest
+			}
+		}
+		var elem T
+		ptr[0] = scanDest(reflect.ValueOf(&elem).Elem())
+		err := rows.Scan(ptr...)
+		if err != nil {
+			return err
+		}
+		v = append(v, elem)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// loadEach runs b, and calls fn for each row.
+func loadEach(ctx context.Context, b *SelectStmt, fn func(rows *sql.Rows, column []string) error) error {
+	runner, log, err := b.readRunner(ctx)
+	if err != nil {
+		return err
+	}
+	timeout := runner.GetTimeout()
+	if timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	startTime := time.Now()
+	query, rows, err := queryRows(ctx, runner, log, b, b.Dialect)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	err = func() error {
+		column, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			err = fn(rows, column)
+			if err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}()
+	if err == ErrMultipleRows {
+		return err
+	}
+	if err != nil {
+		bound := usePreparedStmt(runner) || useBindParams(runner)
+		err = newQueryError("load", b, b.Dialect, query, bound, startTime, classifyError(b.Dialect, err))
+		return log.EventErrKv("dbr.select.load.scan", err, kvs{
+			"sql": query,
+		})
+	}
+	return nil
+}
+
+// loadFirst loads the first row into T like LoadOne.
+func loadFirst[T any](ctx context.Context, b *SelectStmt) (T, error) {
+	var v T
+	err := b.LoadOneContext(ctx, &v)
+	return v, err
+}
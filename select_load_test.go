@@ -0,0 +1,85 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFunc(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess := NewConnection(db, dialect.MySQL, nil).NewSession(nil)
+	ctx := context.Background()
+	people := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(1, "a", "a@b.c").
+			AddRow(2, "b", "b@b.c")
+	}
+	stmt := sess.Select("*").From("dbr_people")
+
+	// all
+	mock.ExpectQuery("SELECT").WillReturnRows(people())
+	all, err := LoadAll[dbrPerson](ctx, stmt)
+	require.NoError(t, err)
+	require.Equal(t, []dbrPerson{
+		{Id: 1, Name: "a", Email: "a@b.c"},
+		{Id: 2, Name: "b", Email: "b@b.c"},
+	}, all)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(people())
+	ptrs, err := LoadAll[*dbrPerson](ctx, stmt)
+	require.NoError(t, err)
+	require.Len(t, ptrs, 2)
+	require.Equal(t, "b", ptrs[1].Name)
+
+	// single
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "a", "a@b.c"))
+	one, err := LoadSingle[dbrPerson](ctx, stmt)
+	require.NoError(t, err)
+	require.Equal(t, dbrPerson{Id: 1, Name: "a", Email: "a@b.c"}, one)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(people())
+	_, err = LoadSingle[dbrPerson](ctx, stmt)
+	require.Equal(t, ErrMultipleRows, err)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = LoadSingle[int64](ctx, stmt)
+	require.Equal(t, ErrNotFound, err)
+
+	// optional
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	opt, err := LoadOptional[dbrPerson](ctx, stmt)
+	require.NoError(t, err)
+	require.Nil(t, opt)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	name, err := LoadOptional[string](ctx, stmt)
+	require.NoError(t, err)
+	require.Equal(t, "a", *name)
+
+	// column
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"created_at", "id"}).AddRow(now, 1).AddRow(now, 2))
+	times, err := LoadColumn[time.Time](ctx, stmt)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{now, now}, times)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(nil))
+	names, err := LoadColumn[NullString](ctx, stmt)
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+	require.False(t, names[0].Valid)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("a"))
+	_, err = LoadColumn[int64](ctx, stmt)
+	var queryErr *QueryError
+	require.ErrorAs(t, err, &queryErr)
+	require.Equal(t, "load", queryErr.Op)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,91 +5,91 @@ import (
 )
 
 // ReturnInt64 executes the SelectStmt and returns the value as an int64.
+//
+// Deprecated: use LoadSingle, which also fails if there is more than one row.
 func (b *SelectStmt) ReturnInt64() (int64, error) {
-	var v int64
-	err := b.LoadOne(&v)
-	return v, err
+	return b.ReturnInt64Context(context.Background())
 }
 
 // ReturnInt64Context executes the SelectStmt and returns the value as an int64.
 // The given context is passed into the query runner.
+//
+// Deprecated: use LoadSingle, which also fails if there is more than one row.
 func (b *SelectStmt) ReturnInt64Context(ctx context.Context) (int64, error) {
-	var v int64
-	err := b.LoadOneContext(ctx, &v)
-	return v, err
+	return loadFirst[int64](ctx, b)
 }
 
 // ReturnInt64s executes the SelectStmt and returns the value as a slice of int64s.
+//
+// Deprecated: use LoadColumn.
 func (b *SelectStmt) ReturnInt64s() ([]int64, error) {
-	var v []int64
-	_, err := b.Load(&v)
-	return v, err
+	return b.ReturnInt64sContext(context.Background())
 }
 
 // ReturnInt64sContext executes the SelectStmt and returns the value as a slice of int64s.
 // The given context is passed into the query runner.
+//
+// Deprecated: use LoadColumn.
 func (b *SelectStmt) ReturnInt64sContext(ctx context.Context) ([]int64, error) {
-	var v []int64
-	_, err := b.LoadContext(ctx, &v)
-	return v, err
+	return LoadColumn[int64](ctx, b)
 }
 
 // ReturnUint64 executes the SelectStmt and returns the value as an uint64.
+//
+// Deprecated: use LoadSingle, which also fails if there is more than one row.
 func (b *SelectStmt) ReturnUint64() (uint64, error) {
-	var v uint64
-	err := b.LoadOne(&v)
-	return v, err
+	return b.ReturnUint64Context(context.Background())
 }
 
 // ReturnUint64Context executes the SelectStmt and returns the value as an uint64.
 // The given context is passed into the query runner.
+//
+// Deprecated: use LoadSingle, which also fails if there is more than one row.
 func (b *SelectStmt) ReturnUint64Context(ctx context.Context) (uint64, error) {
-	var v uint64
-	err := b.LoadOneContext(ctx, &v)
-	return v, err
+	return loadFirst[uint64](ctx, b)
 }
 
 // ReturnUint64s executes the SelectStmt and returns the value as a slice of uint64s.
+//
+// Deprecated: use LoadColumn.
 func (b *SelectStmt) ReturnUint64s() ([]uint64, error) {
-	var v []uint64
-	_, err := b.Load(&v)
-	return v, err
+	return b.ReturnUint64sContext(context.Background())
 }
 
 // ReturnUint64sContext executes the SelectStmt and returns the value as a slice of uint64s.
 // The given context is passed into the query runner.
+//
+// Deprecated: use LoadColumn.
 func (b *SelectStmt) ReturnUint64sContext(ctx context.Context) ([]uint64, error) {
-	var v []uint64
-	_, err := b.LoadContext(ctx, &v)
-	return v, err
+	return LoadColumn[uint64](ctx, b)
 }
 
 // ReturnString executes the SelectStmt and returns the value as a string.
+//
+// Deprecated: use LoadSingle, which also fails if there is more than one row.
 func (b *SelectStmt) ReturnString() (string, error) {
-	var v string
-	err := b.LoadOne(&v)
-	return v, err
+	return b.ReturnStringContext(context.Background())
 }
 
 // ReturnStringContext executes the SelectStmt and returns the value as a string.
 // The given context is passed into the query runner.
+//
+// Deprecated: use LoadSingle, which also fails if there is more than one row.
 func (b *SelectStmt) ReturnStringContext(ctx context.Context) (string, error) {
-	var v string
-	err := b.LoadOneContext(ctx, &v)
-	return v, err
+	return loadFirst[string](ctx, b)
 }
 
 // ReturnStrings executes the SelectStmt and returns the value as a slice of strings.
+//
+// Deprecated: use LoadColumn.
 func (b *SelectStmt) ReturnStrings() ([]string, error) {
-	var v []string
-	_, err := b.Load(&v)
-	return v, err
+	return b.ReturnStringsContext(context.Background())
 }
 
 // ReturnStringsContext executes the SelectStmt and returns the value as a slice of strings.
 // The given context is passed into the query runner.
+//
+// Deprecated: use LoadColumn.
 func (b *SelectStmt) ReturnStringsContext(ctx context.Context) ([]string, error) {
-	var v []string
-	_, err := b.LoadContext(ctx, &v)
-	return v, err
+	return LoadColumn[string](ctx, b)
 }
@@ -205,4 +205,39 @@ func TestPostgresArray(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, []int64{1, 2, 3}, ns)
+
+	// INSERT INTO "array_table" ("val") VALUES (ARRAY[4,5])
+	_, err = sess.InsertInto("array_table").
+		Pair("val", Array([]int64{4, 5})).
+		Exec()
+	require.NoError(t, err)
+
+	var vals [][]int64
+	_, err = sess.Select("val").From("array_table").Where(ArrayContains("val", []int64{4})).Load(&vals)
+	require.NoError(t, err)
+	require.Equal(t, [][]int64{{4, 5}}, vals)
+}
+
+func TestOracleSelectStmt(t *testing.T) {
+	for _, test := range []struct {
+		builder *SelectStmt
+		want    string
+	}{
+		{
+			builder: Select("a").From("table").Where(Eq("b", "x")).OrderAsc("a").Limit(3).Offset(4),
+			want:    `SELECT a FROM table WHERE ("b" = 'x') ORDER BY a ASC OFFSET 4 ROWS FETCH NEXT 3 ROWS ONLY`,
+		},
+		{
+			builder: Select("a").From("table").Limit(3),
+			want:    `SELECT a FROM table FETCH NEXT 3 ROWS ONLY`,
+		},
+		{
+			builder: Select("a").From("table").Offset(4),
+			want:    `SELECT a FROM table OFFSET 4 ROWS`,
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.builder}, dialect.Oracle)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+	}
 }
@@ -0,0 +1,314 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Sharder maps a shard key to one of n shards.
+type Sharder interface {
+	Shard(key interface{}, n int) (int, error)
+}
+
+// ShardFunc implements Sharder.
+type ShardFunc func(key interface{}, n int) (int, error)
+
+// Shard calls itself to map key.
+func (f ShardFunc) Shard(key interface{}, n int) (int, error) {
+	return f(key, n)
+}
+
+// HashSharder maps keys to shards by the FNV-1a hash of their string form.
+var HashSharder Sharder = ShardFunc(func(key interface{}, n int) (int, error) {
+	h := fnv.New32a()
+	fmt.Fprint(h, key)
+	return int(h.Sum32() % uint32(n)), nil
+})
+
+// RangeSharder maps integer keys to shards by upper bounds.
+// Keys below RangeSharder[i] and not below RangeSharder[i-1] are in shard i.
+type RangeSharder []int64
+
+// Shard finds the range of key.
+func (r RangeSharder) Shard(key interface{}, n int) (int, error) {
+	v := reflect.ValueOf(key)
+	var k int64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		k = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		k = int64(v.Uint())
+	default:
+		return 0, ErrShardNotFound
+	}
+	i := sort.Search(len(r), func(i int) bool { return k < r[i] })
+	if i >= len(r) || i >= n {
+		return 0, ErrShardNotFound
+	}
+	return i, nil
+}
+
+// LookupSharder maps keys to shards with a table.
+type LookupSharder map[interface{}]int
+
+// Shard looks up key.
+func (l LookupSharder) Shard(key interface{}, n int) (int, error) {
+	i, ok := l[key]
+	if !ok || i < 0 || i >= n {
+		return 0, ErrShardNotFound
+	}
+	return i, nil
+}
+
+type shardKey struct{}
+
+// WithShardKey returns a context in which ShardedSession
+// runs statements on the shard of key.
+func WithShardKey(ctx context.Context, key interface{}) context.Context {
+	return context.WithValue(ctx, shardKey{}, key)
+}
+
+// ShardedSession runs each statement on one of several shards.
+//
+// Statements that are created from ShardedSession run on the session of the
+// shard of their key, which is set with Shard on the statement, or with
+// WithShardKey in the context. Use Shard to get the session of a key directly,
+// for example to begin a transaction.
+//
+// All shards must have the same Dialect.
+type ShardedSession struct {
+	EventReceiver
+	Dialect
+	Timeout time.Duration
+	Sharder Sharder
+	Shards  []*Session
+}
+
+// NewShardedSession creates a ShardedSession with a session for each Connection.
+// If log is nil, the EventReceiver of the first Connection is used.
+func NewShardedSession(sharder Sharder, log EventReceiver, conn ...*Connection) *ShardedSession {
+	s := &ShardedSession{Sharder: sharder}
+	for _, c := range conn {
+		s.Shards = append(s.Shards, c.NewSession(log))
+	}
+	if len(s.Shards) > 0 {
+		s.EventReceiver = s.Shards[0].EventReceiver
+		s.Dialect = s.Shards[0].Dialect
+	}
+	return s
+}
+
+// Shard returns the session of the shard of key.
+func (s *ShardedSession) Shard(key interface{}) (*Session, error) {
+	if len(s.Shards) == 0 {
+		return nil, ErrShardNotFound
+	}
+	i, err := s.Sharder.Shard(key, len(s.Shards))
+	if err != nil {
+		return nil, err
+	}
+	return s.Shards[i], nil
+}
+
+// ShardContext returns the session of the shard of the key in ctx.
+func (s *ShardedSession) ShardContext(ctx context.Context) (*Session, error) {
+	key := ctx.Value(shardKey{})
+	if key == nil {
+		return nil, ErrNoShardKey
+	}
+	return s.Shard(key)
+}
+
+// shardRunner returns the session of the shard of builder and its log
+// if runner is a ShardedSession, so that the statement runs like it is
+// created from the session. The shard key of builder is used before the key
+// in ctx.
+func shardRunner(ctx context.Context, runner Runner, log EventReceiver, builder Builder) (Runner, EventReceiver, error) {
+	s, ok := runner.(*ShardedSession)
+	if !ok {
+		return runner, log, nil
+	}
+	var key interface{}
+	switch b := builder.(type) {
+	case *SelectStmt:
+		key = b.shard
+	case *InsertStmt:
+		key = b.shard
+	case *UpdateStmt:
+		key = b.shard
+	case *DeleteStmt:
+		key = b.shard
+	}
+	var sess *Session
+	var err error
+	if key != nil {
+		sess, err = s.Shard(key)
+	} else {
+		sess, err = s.ShardContext(ctx)
+	}
+	if err != nil {
+		return nil, nil, log.EventErr("dbr.shard.error", err)
+	}
+	return sess, sess.EventReceiver, nil
+}
+
+// GetTimeout returns current timeout enforced in session.
+func (s *ShardedSession) GetTimeout() time.Duration {
+	return s.Timeout
+}
+
+// ExecContext runs query on the shard of the key in ctx.
+func (s *ShardedSession) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	sess, err := s.ShardContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sess.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs query on the shard of the key in ctx.
+func (s *ShardedSession) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	sess, err := s.ShardContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sess.QueryContext(ctx, query, args...)
+}
+
+// Select creates a SelectStmt.
+func (s *ShardedSession) Select(column ...interface{}) *SelectStmt {
+	b := Select(column...)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// SelectBySql creates a SelectStmt from raw query.
+func (s *ShardedSession) SelectBySql(query string, value ...interface{}) *SelectStmt {
+	b := SelectBySql(query, value...)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// InsertInto creates an InsertStmt.
+func (s *ShardedSession) InsertInto(table string) *InsertStmt {
+	b := InsertInto(table)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// InsertBySql creates an InsertStmt from raw query.
+func (s *ShardedSession) InsertBySql(query string, value ...interface{}) *InsertStmt {
+	b := InsertBySql(query, value...)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// Update creates an UpdateStmt.
+func (s *ShardedSession) Update(table string) *UpdateStmt {
+	b := Update(table)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// UpdateBySql creates an UpdateStmt with raw query.
+func (s *ShardedSession) UpdateBySql(query string, value ...interface{}) *UpdateStmt {
+	b := UpdateBySql(query, value...)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// DeleteFrom creates a DeleteStmt.
+func (s *ShardedSession) DeleteFrom(table string) *DeleteStmt {
+	b := DeleteFrom(table)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// DeleteBySql creates a DeleteStmt with raw query.
+func (s *ShardedSession) DeleteBySql(query string, value ...interface{}) *DeleteStmt {
+	b := DeleteBySql(query, value...)
+	b.Runner = s
+	b.EventReceiver = s.EventReceiver
+	b.Dialect = s.Dialect
+	return b
+}
+
+// Gather runs stmt on every shard concurrently, and appends all rows to value,
+// which must be a pointer to a slice. Rows are appended in the order of shards,
+// so ORDER BY and LIMIT apply to each shard, not to the result.
+// If a shard fails, the queries of the other shards are canceled.
+func (s *ShardedSession) Gather(ctx context.Context, stmt *SelectStmt, value interface{}) (int, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return 0, ErrInvalidPointer
+	}
+	v = v.Elem()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make([]reflect.Value, len(s.Shards))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		errShard int
+	)
+	for i, sess := range s.Shards {
+		wg.Add(1)
+		go func(i int, sess *Session) {
+			defer wg.Done()
+			b := *stmt
+			b.Runner = sess
+			b.EventReceiver = sess.EventReceiver
+			b.Dialect = sess.Dialect
+			result[i] = reflect.New(v.Type())
+			_, err := b.LoadContext(ctx, result[i].Interface())
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					// the other shards fail with context.Canceled after this.
+					firstErr = err
+					errShard = i
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(i, sess)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, s.EventErrKv("dbr.gather", firstErr, kvs{
+			"shard": strconv.Itoa(errShard),
+		})
+	}
+	count := 0
+	for i := range result {
+		rows := result[i].Elem()
+		v.Set(reflect.AppendSlice(v, rows))
+		count += rows.Len()
+	}
+	return count, nil
+}
@@ -0,0 +1,133 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharder(t *testing.T) {
+	i, err := RangeSharder{100, 200}.Shard(150, 2)
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+	_, err = RangeSharder{100, 200}.Shard(uint(250), 2)
+	require.Equal(t, ErrShardNotFound, err)
+
+	i, err = LookupSharder{"acme": 1}.Shard("acme", 2)
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+	_, err = LookupSharder{"acme": 1}.Shard("other", 2)
+	require.Equal(t, ErrShardNotFound, err)
+
+	a, err := HashSharder.Shard("acme", 4)
+	require.NoError(t, err)
+	b, err := HashSharder.Shard("acme", 4)
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+	require.True(t, a >= 0 && a < 4)
+}
+
+func TestShardedSession(t *testing.T) {
+	var conn []*Connection
+	var mock []sqlmock.Sqlmock
+	for i := 0; i < 2; i++ {
+		db, m, err := sqlmock.New()
+		require.NoError(t, err)
+		conn = append(conn, &Connection{
+			DB:            db,
+			EventReceiver: &NullEventReceiver{},
+			Dialect:       dialect.PostgreSQL,
+		})
+		mock = append(mock, m)
+	}
+	sess := NewShardedSession(LookupSharder{"a": 0, "b": 1}, nil, conn...)
+
+	mock[1].ExpectExec(`INSERT INTO "dbr_people" \("name"\) VALUES \('b'\)`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	_, err := sess.InsertInto("dbr_people").Columns("name").Values("b").
+		ExecContext(WithShardKey(context.Background(), "b"))
+	require.NoError(t, err)
+
+	mock[0].ExpectQuery(`SELECT name FROM dbr_people`).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	var name string
+	err = sess.Select("name").From("dbr_people").LoadOneContext(WithShardKey(context.Background(), "a"), &name)
+	require.NoError(t, err)
+	require.Equal(t, "a", name)
+
+	_, err = sess.Select("name").From("dbr_people").Load(&name)
+	require.ErrorIs(t, err, ErrNoShardKey)
+	_, err = sess.DeleteFrom("dbr_people").ExecContext(WithShardKey(context.Background(), "c"))
+	require.ErrorIs(t, err, ErrShardNotFound)
+
+	// the key of the statement is used before the key in the context,
+	// and the statement runs with the options of the shard session.
+	sess.Shards[1].BindParams = true
+	mock[1].ExpectExec(`UPDATE "dbr_people" SET "name" = \$1 WHERE \("id" = \$2\)`).
+		WithArgs("c", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.Update("dbr_people").Set("name", "c").Where(Eq("id", 1)).Shard("b").
+		ExecContext(WithShardKey(context.Background(), "a"))
+	require.NoError(t, err)
+
+	mock[1].ExpectQuery(`SELECT name FROM dbr_people WHERE \(id = \$1\)`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("c"))
+	err = sess.Select("name").From("dbr_people").Where("id = ?", 1).Shard("b").LoadOne(&name)
+	require.NoError(t, err)
+	require.Equal(t, "c", name)
+	sess.Shards[1].BindParams = false
+
+	shard, err := sess.Shard("b")
+	require.NoError(t, err)
+	require.Equal(t, conn[1], shard.Connection)
+
+	// scatter-gather
+	mock[0].ExpectQuery(`SELECT id, name FROM dbr_people ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(3, "c"))
+	mock[1].ExpectQuery(`SELECT id, name FROM dbr_people ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "b"))
+	var people []dbrPerson
+	n, err := sess.Gather(context.Background(), Select("id", "name").From("dbr_people").OrderBy("id"), &people)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []dbrPerson{{Id: 1, Name: "a"}, {Id: 3, Name: "c"}, {Id: 2, Name: "b"}}, people)
+
+	_, err = sess.Gather(context.Background(), Select("id").From("dbr_people"), &name)
+	require.Equal(t, ErrInvalidPointer, err)
+
+	for _, m := range mock {
+		require.NoError(t, m.ExpectationsWereMet())
+	}
+}
+
+func TestGatherCancel(t *testing.T) {
+	var conn []*Connection
+	var mock []sqlmock.Sqlmock
+	for i := 0; i < 2; i++ {
+		s, m := newMockSession(t, dialect.PostgreSQL, nil)
+		conn = append(conn, s.Connection)
+		mock = append(mock, m)
+	}
+	sess := NewShardedSession(LookupSharder{}, nil, conn...)
+
+	// the first error cancels the query of the other shard,
+	// which may not have started yet.
+	boom := errors.New("boom")
+	mock[0].ExpectQuery(`SELECT id FROM dbr_people`).
+		WillDelayFor(time.Minute).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock[1].ExpectQuery(`SELECT id FROM dbr_people`).
+		WillReturnError(boom)
+	var id []int64
+	start := time.Now()
+	_, err := sess.Gather(context.Background(), Select("id").From("dbr_people"), &id)
+	require.ErrorIs(t, err, boom)
+	require.Less(t, time.Since(start), time.Minute)
+	require.NoError(t, mock[1].ExpectationsWereMet())
+}
@@ -8,6 +8,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// newMockSession creates a session of dialect d on a sqlmock database
+// that matches queries exactly.
+func newMockSession(t *testing.T, d Dialect, log EventReceiver) (*Session, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	return NewConnection(db, d, nil).NewSession(log), mock
+}
+
 func TestSQLMock(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -30,3 +38,92 @@ func TestSQLMock(t *testing.T) {
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestSQLMockBindParams(t *testing.T) {
+	for _, test := range []struct {
+		d           Dialect
+		selectQuery string
+		updateQuery string
+	}{
+		{
+			d:           dialect.MySQL,
+			selectQuery: "SELECT id FROM suggestions WHERE \\(`id` IN \\(\\?,\\?\\)\\) AND \\(`title` = \\?\\)",
+			updateQuery: "UPDATE `suggestions` SET `title` = \\? WHERE \\(`id` = \\?\\)",
+		},
+		{
+			d:           dialect.PostgreSQL,
+			selectQuery: `SELECT id FROM suggestions WHERE \("id" IN \(\$1,\$2\)\) AND \("title" = \$3\)`,
+			updateQuery: `UPDATE "suggestions" SET "title" = \$1 WHERE \("id" = \$2\)`,
+		},
+		{
+			d:           dialect.SQLite3,
+			selectQuery: `SELECT id FROM suggestions WHERE \("id" IN \(\?,\?\)\) AND \("title" = \?\)`,
+			updateQuery: `UPDATE "suggestions" SET "title" = \? WHERE \("id" = \?\)`,
+		},
+		{
+			d:           dialect.MSSQL,
+			selectQuery: `SELECT id FROM suggestions WHERE \("id" IN \(@p1,@p2\)\) AND \("title" = @p3\)`,
+			updateQuery: `UPDATE "suggestions" SET "title" = @p1 WHERE \("id" = @p2\)`,
+		},
+	} {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		conn := &Connection{
+			DB:            db,
+			EventReceiver: &NullEventReceiver{},
+			Dialect:       test.d,
+			BindParams:    true,
+		}
+		sess := conn.NewSession(nil)
+
+		mock.ExpectQuery(test.selectQuery).
+			WithArgs(1, 2, "a'b").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		id, err := sess.Select("id").From("suggestions").
+			Where(Eq("id", []int{1, 2})).
+			Where(Eq("title", "a'b")).
+			ReturnInt64s()
+		require.NoError(t, err)
+		require.Equal(t, []int64{1}, id)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(test.updateQuery).
+			WithArgs("c", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		tx, err := sess.Begin()
+		require.NoError(t, err)
+		_, err = tx.Update("suggestions").Set("title", "c").Where(Eq("id", 1)).Exec()
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestSQLMockIterateSlice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.MySQL,
+	}
+	sess := conn.NewSession(nil)
+
+	// each Scan appends a row to the slice
+	mock.ExpectQuery("SELECT id FROM suggestions").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	iter, err := sess.Select("id").From("suggestions").Iterate()
+	require.NoError(t, err)
+	var id []int64
+	for iter.Next() {
+		require.NoError(t, iter.Scan(&id))
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, []int64{1, 2}, id)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,231 @@
+package dbr
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+)
+
+// StmtCache is an LRU cache of prepared statements keyed by SQL text.
+//
+// When a Session or Tx has a StmtCache, queries are built with dialect
+// placeholders instead of interpolated values, prepared once, and run through
+// the cached *sql.Stmt with values as bind parameters. This lets the database
+// reuse query plans for hot queries.
+type StmtCache struct {
+	db   *sql.DB
+	size int
+	log  EventReceiver
+
+	mu sync.Mutex
+	ll *list.List
+	m  map[string]*list.Element
+}
+
+type stmtCacheEntry struct {
+	query string
+	stmt  *sql.Stmt
+	// refs is the number of users of stmt, guarded by StmtCache.mu.
+	// An evicted entry is closed when it has no users.
+	refs    int
+	evicted bool
+}
+
+// NewStmtCache creates a StmtCache that keeps up to size statements prepared on db.
+// log can be nil to ignore logging.
+func NewStmtCache(db *sql.DB, size int, log EventReceiver) *StmtCache {
+	if log == nil {
+		log = nullReceiver
+	}
+	return &StmtCache{
+		db:   db,
+		size: size,
+		log:  log,
+		ll:   list.New(),
+		m:    make(map[string]*list.Element),
+	}
+}
+
+// Len returns the number of cached statements.
+func (c *StmtCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// PrepareContext returns the cached statement for query, or prepares it.
+//
+// The statement is not closed by eviction until release is called,
+// which must be done after the statement is used.
+func (c *StmtCache) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, release func(), err error) {
+	c.mu.Lock()
+	if e, ok := c.m[query]; ok {
+		c.ll.MoveToFront(e)
+		entry := e.Value.(*stmtCacheEntry)
+		entry.refs++
+		c.mu.Unlock()
+		return entry.stmt, c.releaser(entry), nil
+	}
+	c.mu.Unlock()
+
+	// prepare without the lock, so other queries are not blocked.
+	stmt, err = c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, c.log.EventErrKv("dbr.prepare.error", err, kvs{
+			"sql": query,
+		})
+	}
+
+	c.mu.Lock()
+	if e, ok := c.m[query]; ok {
+		// prepared concurrently
+		c.ll.MoveToFront(e)
+		entry := e.Value.(*stmtCacheEntry)
+		entry.refs++
+		c.mu.Unlock()
+		stmt.Close()
+		return entry.stmt, c.releaser(entry), nil
+	}
+	entry := &stmtCacheEntry{query: query, stmt: stmt, refs: 1}
+	c.m[query] = c.ll.PushFront(entry)
+	var evicted []*stmtCacheEntry
+	for c.size > 0 && c.ll.Len() > c.size {
+		evicted = append(evicted, c.evict(c.ll.Back())...)
+	}
+	c.mu.Unlock()
+
+	for _, entry := range evicted {
+		c.close(entry, "dbr.prepare.evict")
+	}
+	return stmt, c.releaser(entry), nil
+}
+
+// evict removes e from the cache, and returns it if it can be closed now.
+// It must be called with c.mu held.
+func (c *StmtCache) evict(e *list.Element) []*stmtCacheEntry {
+	c.ll.Remove(e)
+	entry := e.Value.(*stmtCacheEntry)
+	delete(c.m, entry.query)
+	entry.evicted = true
+	if entry.refs > 0 {
+		return nil
+	}
+	return []*stmtCacheEntry{entry}
+}
+
+// releaser returns a func that releases a reference to entry once.
+func (c *StmtCache) releaser(entry *stmtCacheEntry) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			entry.refs--
+			closing := entry.evicted && entry.refs == 0
+			c.mu.Unlock()
+			if closing {
+				c.close(entry, "dbr.prepare.evict")
+			}
+		})
+	}
+}
+
+// Close closes all cached statements.
+// Statements that are in use are closed when they are released.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	var entries []*stmtCacheEntry
+	for c.ll.Len() > 0 {
+		entries = append(entries, c.evict(c.ll.Front())...)
+	}
+	c.mu.Unlock()
+
+	var err error
+	for _, entry := range entries {
+		if closeErr := c.close(entry, "dbr.prepare.close"); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (c *StmtCache) close(entry *stmtCacheEntry, eventName string) error {
+	// a statement with open rows is closed after the rows are closed.
+	err := entry.stmt.Close()
+	if err != nil {
+		return c.log.EventErrKv(eventName+".error", err, kvs{
+			"sql": entry.query,
+		})
+	}
+	c.log.EventKv(eventName, kvs{
+		"sql":  entry.query,
+		"size": strconv.Itoa(c.size),
+	})
+	return nil
+}
+
+// stmtPreparer is implemented by runners that can run queries
+// through prepared statements.
+type stmtPreparer interface {
+	stmtCache() *StmtCache
+	// prepareContext returns a statement for query,
+	// and a func to call after the statement is used.
+	prepareContext(ctx context.Context, query string) (*sql.Stmt, func(), error)
+}
+
+func (sess *Session) stmtCache() *StmtCache {
+	return sess.StmtCache
+}
+
+func (sess *Session) prepareContext(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	return sess.StmtCache.PrepareContext(ctx, query)
+}
+
+func (tx *Tx) stmtCache() *StmtCache {
+	return tx.StmtCache
+}
+
+// prepareContext prepares query once per transaction
+// from the statement cached in the connection.
+// The cached statement is held until the transaction ends.
+func (tx *Tx) prepareContext(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	if tx.parent != nil {
+		// nested transactions share statements.
+		return tx.parent.prepareContext(ctx, query)
+	}
+	tx.stmtMu.Lock()
+	defer tx.stmtMu.Unlock()
+	if stmt, ok := tx.stmts[query]; ok {
+		return stmt, func() {}, nil
+	}
+	stmt, release, err := tx.StmtCache.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	// closed when the transaction ends
+	stmt = tx.Tx.StmtContext(ctx, stmt)
+	if tx.stmts == nil {
+		tx.stmts = make(map[string]*sql.Stmt)
+	}
+	tx.stmts[query] = stmt
+	tx.stmtRelease = append(tx.stmtRelease, release)
+	return stmt, func() {}, nil
+}
+
+// releaseStmts releases the cached statements after the transaction ends.
+func (tx *Tx) releaseStmts() {
+	tx.stmtMu.Lock()
+	release := tx.stmtRelease
+	tx.stmtRelease = nil
+	tx.stmtMu.Unlock()
+	for _, f := range release {
+		f()
+	}
+}
+
+// usePreparedStmt reports whether runner has prepared statement mode enabled.
+func usePreparedStmt(runner Runner) bool {
+	p, ok := runner.(stmtPreparer)
+	return ok && p.stmtCache() != nil
+}
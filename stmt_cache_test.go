@@ -0,0 +1,165 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type testEventReceiver struct {
+	NullEventReceiver
+	events []string
+}
+
+func (r *testEventReceiver) EventKv(eventName string, kvs map[string]string) {
+	r.events = append(r.events, eventName)
+}
+
+func (r *testEventReceiver) EventErrKv(eventName string, err error, kvs map[string]string) error {
+	r.events = append(r.events, eventName)
+	return err
+}
+
+func TestStmtCache(t *testing.T) {
+	log := &testEventReceiver{}
+	sess, mock := newMockSession(t, dialect.PostgreSQL, nil)
+	sess.StmtCache = NewStmtCache(sess.DB, 1, log)
+
+	selectStmt := mock.ExpectPrepare(`SELECT id FROM dbr_people WHERE ("id" IN ($1,$2)) AND ("name" = $3)`).WillBeClosed()
+	selectStmt.ExpectQuery().WithArgs(1, 2, "a").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	selectStmt.ExpectQuery().WithArgs(3, 4, "b").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	for _, args := range [][]interface{}{{1, 2, "a"}, {3, 4, "b"}} {
+		id, err := sess.Select("id").From("dbr_people").
+			Where(Eq("id", args[:2])).
+			Where(Eq("name", args[2])).
+			ReturnInt64s()
+		require.NoError(t, err)
+		require.Len(t, id, 1)
+	}
+	require.Equal(t, 1, sess.StmtCache.Len())
+
+	// evicts the select statement
+	updateStmt := mock.ExpectPrepare(`UPDATE "dbr_people" SET "name" = $1 WHERE ("id" = $2)`)
+	updateStmt.ExpectExec().WithArgs("c", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err := sess.Update("dbr_people").Set("name", "c").Where(Eq("id", 1)).Exec()
+	require.NoError(t, err)
+	require.Equal(t, 1, sess.StmtCache.Len())
+	require.Equal(t, []string{"dbr.prepare.evict"}, log.events)
+
+	// reuses the cached statement in a transaction
+	mock.ExpectBegin()
+	updateStmt.ExpectExec().WithArgs("d", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	updateStmt.ExpectExec().WithArgs("e", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	_, err = tx.Update("dbr_people").Set("name", "d").Where(Eq("id", 2)).Exec()
+	require.NoError(t, err)
+	_, err = tx.Update("dbr_people").Set("name", "e").Where(Eq("id", 3)).Exec()
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCachePrepareError(t *testing.T) {
+	log := &testEventReceiver{}
+	sess, mock := newMockSession(t, dialect.MySQL, nil)
+	sess.StmtCache = NewStmtCache(sess.DB, 10, log)
+
+	mock.ExpectPrepare("SELECT id FROM missing").WillReturnError(ErrNotSupported)
+	_, err := sess.Select("id").From("missing").ReturnInt64s()
+	require.ErrorIs(t, err, ErrNotSupported)
+	require.Equal(t, []string{"dbr.prepare.error"}, log.events)
+	require.Equal(t, 0, sess.StmtCache.Len())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// testDriver accepts any statement, and returns one row of id 1 for queries.
+type testDriver struct{}
+
+func (testDriver) Open(string) (driver.Conn, error)             { return testDriver{}, nil }
+func (testDriver) Connect(context.Context) (driver.Conn, error) { return testDriver{}, nil }
+func (testDriver) Driver() driver.Driver                        { return testDriver{} }
+func (testDriver) Prepare(query string) (driver.Stmt, error)    { return testDriver{}, nil }
+func (testDriver) Close() error                                 { return nil }
+func (testDriver) Begin() (driver.Tx, error)                    { return testDriver{}, nil }
+func (testDriver) Commit() error                                { return nil }
+func (testDriver) Rollback() error                              { return nil }
+func (testDriver) NumInput() int                                { return -1 }
+func (testDriver) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (testDriver) Query(args []driver.Value) (driver.Rows, error) {
+	return &testDriverRows{}, nil
+}
+
+type testDriverRows struct {
+	done bool
+}
+
+func (r *testDriverRows) Columns() []string { return []string{"id"} }
+func (r *testDriverRows) Close() error      { return nil }
+func (r *testDriverRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestStmtCacheConcurrentEvict(t *testing.T) {
+	db := sql.OpenDB(testDriver{})
+	defer db.Close()
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.PostgreSQL,
+		StmtCache:     NewStmtCache(db, 1, nil),
+	}
+	sess := conn.NewSession(nil)
+
+	// each query evicts the other, while it may still be in use.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if (i+j)%2 == 0 {
+					id, err := sess.Select("id").From("dbr_people").Where(Eq("id", j)).ReturnInt64s()
+					require.NoError(t, err)
+					require.Equal(t, []int64{1}, id)
+				} else {
+					_, err := sess.Update("dbr_people").Set("name", "a").Where(Eq("id", j)).Exec()
+					require.NoError(t, err)
+				}
+			}
+		}(i)
+	}
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	for j := 0; j < 100; j++ {
+		// the statement of the transaction is kept until it ends.
+		_, err := tx.Update("dbr_people").Set("name", "b").Where(Eq("id", j)).Exec()
+		require.NoError(t, err)
+	}
+	require.NoError(t, tx.Commit())
+
+	wg.Wait()
+	require.NoError(t, conn.StmtCache.Close())
+	require.Equal(t, 0, conn.StmtCache.Len())
+}
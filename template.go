@@ -0,0 +1,152 @@
+package dbr
+
+import (
+	"database/sql"
+	"strings"
+)
+
+type templateParam struct {
+	name  string
+	index int
+}
+
+// Param is a named parameter of a Template.
+// Its value is given with sql.Named when the template is bound.
+func Param(name string) Builder {
+	return &templateParam{name: name, index: -1}
+}
+
+// Arg is a positional parameter of a Template.
+// Its value is the index-th argument that is not sql.NamedArg
+// when the template is bound.
+func Arg(index int) Builder {
+	return &templateParam{index: index}
+}
+
+func (p *templateParam) Build(_ Dialect, _ Buffer) error {
+	return ErrTemplateParam
+}
+
+type templateSlot struct {
+	param *templateParam
+	pos   int
+}
+
+// Template is a statement that is built once, and then run many times
+// with different arguments.
+//
+// Binding a template only encodes the new arguments. The shape of the
+// statement is fixed when it is compiled, so a parameter compared with Eq
+// is always rendered with =, even if the argument is nil or a slice.
+// Use Expr for IN and IS NULL.
+//
+// Template is safe for concurrent use.
+type Template struct {
+	Dialect Dialect
+
+	// query has a placeholder for each parameter,
+	// and escaped placeholders everywhere else.
+	query string
+	param []*templateParam
+}
+
+// Compile builds builder in dialect d into a Template.
+// Parameters are created with Param and Arg.
+func Compile(builder Builder, d Dialect) (*Template, error) {
+	i := interpolator{
+		Buffer:  NewBuffer(),
+		Dialect: d,
+		Compile: true,
+	}
+	err := i.encodePlaceholder(builder, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := i.String()
+	t := &Template{Dialect: d}
+	query := new(strings.Builder)
+	start := 0
+	for _, slot := range i.Slot {
+		query.WriteString(strings.ReplaceAll(s[start:slot.pos], placeholder, escapedPlaceholder))
+		query.WriteString(placeholder)
+		t.param = append(t.param, slot.param)
+		start = slot.pos
+	}
+	query.WriteString(strings.ReplaceAll(s[start:], placeholder, escapedPlaceholder))
+	t.query = query.String()
+	return t, nil
+}
+
+// String returns the compiled SQL with ? for each parameter.
+func (t *Template) String() string {
+	return t.query
+}
+
+// Bind returns a Builder that builds the template with arg.
+// Arguments of type sql.NamedArg are used for Param, and the others for Arg.
+func (t *Template) Bind(arg ...interface{}) Builder {
+	return &templateStmt{Template: t, arg: arg}
+}
+
+// Select creates a SelectStmt on runner from the template bound to arg.
+func (t *Template) Select(runner SessionRunner, arg ...interface{}) *SelectStmt {
+	return runner.SelectBySql(placeholder, t.Bind(arg...))
+}
+
+// Insert creates an InsertStmt on runner from the template bound to arg.
+func (t *Template) Insert(runner SessionRunner, arg ...interface{}) *InsertStmt {
+	return runner.InsertBySql(placeholder, t.Bind(arg...))
+}
+
+// Update creates an UpdateStmt on runner from the template bound to arg.
+func (t *Template) Update(runner SessionRunner, arg ...interface{}) *UpdateStmt {
+	return runner.UpdateBySql(placeholder, t.Bind(arg...))
+}
+
+// Delete creates a DeleteStmt on runner from the template bound to arg.
+func (t *Template) Delete(runner SessionRunner, arg ...interface{}) *DeleteStmt {
+	return runner.DeleteBySql(placeholder, t.Bind(arg...))
+}
+
+type templateStmt struct {
+	*Template
+	arg []interface{}
+}
+
+func (b *templateStmt) Build(d Dialect, buf Buffer) error {
+	if d != b.Dialect {
+		return ErrNotSupported
+	}
+	var positional []interface{}
+	var named map[string]interface{}
+	for _, arg := range b.arg {
+		if v, ok := arg.(sql.NamedArg); ok {
+			if named == nil {
+				named = make(map[string]interface{})
+			}
+			named[v.Name] = v.Value
+			continue
+		}
+		positional = append(positional, arg)
+	}
+
+	value := make([]interface{}, len(b.param))
+	for n, param := range b.param {
+		if param.name != "" {
+			v, ok := named[param.name]
+			if !ok {
+				return ErrTemplateArg
+			}
+			value[n] = v
+			continue
+		}
+		if param.index < 0 || param.index >= len(positional) {
+			return ErrTemplateArg
+		}
+		value[n] = positional[param.index]
+	}
+	buf.WriteString(b.query)
+	buf.WriteValue(value...)
+	return nil
+}
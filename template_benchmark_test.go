@@ -0,0 +1,42 @@
+package dbr
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func BenchmarkTemplate(b *testing.B) {
+	build := func(name, id interface{}) Builder {
+		return Select("id", "name", "email", "created_at").
+			From("dbr_people").
+			Join("dbr_suggestions", "dbr_suggestions.person_id = dbr_people.id").
+			Where(And(Eq("name", name), Gt("created_at", "2020-01-01"), Neq("email", ""))).
+			Where(Expr("dbr_people.id IN ?", id)).
+			OrderDesc("created_at").
+			Limit(10)
+	}
+	id := []int64{1, 2, 3, 4, 5}
+
+	b.Run("builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := InterpolateForDialect("?", []interface{}{build("a", id)}, dialect.PostgreSQL)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("template", func(b *testing.B) {
+		tmpl, err := Compile(build(Param("name"), Arg(0)), dialect.PostgreSQL)
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := InterpolateForDialect("?", []interface{}{tmpl.Bind(id, sql.Named("name", "a"))}, dialect.PostgreSQL)
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package dbr
+
+import (
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	stmt := Select("*").From("dbr_people").
+		Where(Eq("name", Param("name"))).
+		Where(Expr("id IN ?", Arg(0))).
+		Where(Eq("email", "a?b@example.com"))
+
+	for _, test := range []struct {
+		d     Dialect
+		query string
+		want  string
+	}{
+		{
+			d:     dialect.MySQL,
+			query: "SELECT * FROM dbr_people WHERE (`name` = ?) AND (id IN ?) AND (`email` = 'a??b@example.com')",
+			want:  "SELECT * FROM dbr_people WHERE (`name` = 'a') AND (id IN (1,2)) AND (`email` = 'a?b@example.com')",
+		},
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT * FROM dbr_people WHERE ("name" = ?) AND (id IN ?) AND ("email" = 'a??b@example.com')`,
+			want:  `SELECT * FROM dbr_people WHERE ("name" = 'a') AND (id IN (1,2)) AND ("email" = 'a?b@example.com')`,
+		},
+	} {
+		tmpl, err := Compile(stmt, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.query, tmpl.String())
+
+		s, err := InterpolateForDialect("?", []interface{}{tmpl.Bind([]int{1, 2}, sql.Named("name", "a"))}, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+
+		// the same statement built without a template
+		s, err = InterpolateForDialect("?", []interface{}{
+			Select("*").From("dbr_people").
+				Where(Eq("name", "a")).
+				Where(Expr("id IN ?", []int{1, 2})).
+				Where(Eq("email", "a?b@example.com")),
+		}, test.d)
+		require.NoError(t, err)
+		require.Equal(t, test.want, s)
+	}
+
+	tmpl, err := Compile(stmt, dialect.MySQL)
+	require.NoError(t, err)
+
+	_, err = InterpolateForDialect("?", []interface{}{tmpl.Bind([]int{1, 2})}, dialect.MySQL)
+	require.Equal(t, ErrTemplateArg, err)
+	_, err = InterpolateForDialect("?", []interface{}{tmpl.Bind(sql.Named("name", "a"))}, dialect.MySQL)
+	require.Equal(t, ErrTemplateArg, err)
+	_, err = InterpolateForDialect("?", []interface{}{tmpl.Bind([]int{1, 2}, sql.Named("name", "a"))}, dialect.PostgreSQL)
+	require.Equal(t, ErrNotSupported, err)
+	_, err = InterpolateForDialect("?", []interface{}{stmt}, dialect.MySQL)
+	require.Equal(t, ErrTemplateParam, err)
+}
+
+func TestTemplateSession(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL, nil)
+	sess.BindParams = true
+
+	selectTmpl, err := Compile(Select("id").From("dbr_people").Where(Eq("name", Arg(0))).Limit(1), dialect.PostgreSQL)
+	require.NoError(t, err)
+	updateTmpl, err := Compile(Update("dbr_people").Set("name", Param("name")).Where(Eq("id", Param("id"))), dialect.PostgreSQL)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT id FROM dbr_people WHERE ("name" = $1) LIMIT 1`).
+		WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`UPDATE "dbr_people" SET "name" = $1 WHERE ("id" = $2)`).
+		WithArgs("b", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var id int64
+	require.NoError(t, selectTmpl.Select(sess, "a").LoadOne(&id))
+	require.Equal(t, int64(1), id)
+
+	_, err = updateTmpl.Update(sess, sql.Named("id", id), sql.Named("name", "b")).Exec()
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,15 +3,35 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Tx is a transaction created by Session.
+// Tx is a transaction created by Session, or a nested transaction
+// created by Tx.
 type Tx struct {
 	EventReceiver
 	Dialect
 	*sql.Tx
-	Timeout time.Duration
+	Timeout    time.Duration
+	StmtCache  *StmtCache
+	BindParams bool
+
+	stmtMu      sync.Mutex
+	stmts       map[string]*sql.Stmt
+	stmtRelease []func()
+
+	// nested transaction
+	parent     *Tx
+	savepoint  string
+	savepoints atomic.Int64
+	done       bool
+	nestedMu   sync.Mutex
+	nested     []*Tx
+
+	hookMu sync.Mutex
+	hooks  []txHook
 }
 
 // GetTimeout returns timeout enforced in Tx.
@@ -32,6 +52,8 @@ func (sess *Session) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, err
 		Dialect:       sess.Dialect,
 		Tx:            tx,
 		Timeout:       sess.GetTimeout(),
+		StmtCache:     sess.StmtCache,
+		BindParams:    sess.BindParams,
 	}, nil
 }
 
@@ -42,21 +64,42 @@ func (sess *Session) Begin() (*Tx, error) {
 
 // Commit finishes the transaction.
 func (tx *Tx) Commit() error {
+	if tx.parent != nil {
+		return tx.commitSavepoint()
+	}
+	if tx.hasOpenNested() {
+		return tx.EventErr("dbr.commit.error", ErrNestedTxOpen)
+	}
+	defer tx.releaseStmts()
 	err := tx.Tx.Commit()
 	if err != nil {
-		return tx.EventErr("dbr.commit.error", err)
+		if err != sql.ErrTxDone {
+			defer tx.runHooks(false)
+		}
+		return tx.EventErr("dbr.commit.error", classifyError(tx.Dialect, err))
 	}
 	tx.Event("dbr.commit")
+	tx.runHooks(true)
 	return nil
 }
 
 // Rollback cancels the transaction.
 func (tx *Tx) Rollback() error {
+	if tx.parent != nil {
+		return tx.rollbackSavepoint()
+	}
+	defer tx.releaseStmts()
 	err := tx.Tx.Rollback()
 	if err != nil {
+		if err != sql.ErrTxDone {
+			defer tx.runHooks(false)
+			defer tx.rollbackNested()
+		}
 		return tx.EventErr("dbr.rollback", err)
 	}
 	tx.Event("dbr.rollback")
+	tx.rollbackNested()
+	tx.runHooks(false)
 	return nil
 }
 
@@ -68,12 +111,23 @@ func (tx *Tx) Rollback() error {
 // Keep in mind the only way to detect an error on the rollback
 // is via the event log.
 func (tx *Tx) RollbackUnlessCommitted() {
+	if tx.parent != nil {
+		if !tx.done {
+			tx.rollbackSavepoint()
+		}
+		return
+	}
+	defer tx.releaseStmts()
 	err := tx.Tx.Rollback()
 	if err == sql.ErrTxDone {
 		// ok
 	} else if err != nil {
 		tx.EventErr("dbr.rollback_unless_committed", err)
+		tx.rollbackNested()
+		tx.runHooks(false)
 	} else {
 		tx.Event("dbr.rollback")
+		tx.rollbackNested()
+		tx.runHooks(false)
 	}
 }
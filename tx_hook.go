@@ -0,0 +1,88 @@
+package dbr
+
+import "fmt"
+
+type txHook struct {
+	on string
+	fn func(committed bool) error
+}
+
+// OnCommit registers fn to run after tx is committed.
+//
+// Hooks run in the order they are registered. Errors and panics of hooks
+// are reported to the EventReceiver, and do not affect other hooks.
+// Hooks of a nested transaction run when the outermost transaction
+// is committed, or when the nested transaction is rolled back.
+func (tx *Tx) OnCommit(fn func() error) {
+	tx.addHook("commit", func(bool) error {
+		return fn()
+	})
+}
+
+// OnRollback registers fn to run after tx is rolled back or fails to commit.
+func (tx *Tx) OnRollback(fn func() error) {
+	tx.addHook("rollback", func(bool) error {
+		return fn()
+	})
+}
+
+// OnComplete registers fn to run after tx is either committed or rolled back.
+func (tx *Tx) OnComplete(fn func(committed bool) error) {
+	tx.addHook("complete", fn)
+}
+
+func (tx *Tx) addHook(on string, fn func(committed bool) error) {
+	tx.hookMu.Lock()
+	tx.hooks = append(tx.hooks, txHook{on: on, fn: fn})
+	tx.hookMu.Unlock()
+}
+
+// takeHooks removes all hooks from tx.
+func (tx *Tx) takeHooks() []txHook {
+	tx.hookMu.Lock()
+	hooks := tx.hooks
+	tx.hooks = nil
+	tx.hookMu.Unlock()
+	return hooks
+}
+
+// runHooks runs hooks after tx ends.
+func (tx *Tx) runHooks(committed bool) {
+	if committed && tx.parent != nil {
+		// the changes are not committed until the parent is.
+		hooks := tx.takeHooks()
+		tx.parent.hookMu.Lock()
+		tx.parent.hooks = append(tx.parent.hooks, hooks...)
+		tx.parent.hookMu.Unlock()
+		return
+	}
+	for _, h := range tx.takeHooks() {
+		switch h.on {
+		case "commit":
+			if !committed {
+				continue
+			}
+		case "rollback":
+			if committed {
+				continue
+			}
+		}
+		tx.runHook(h, committed)
+	}
+}
+
+func (tx *Tx) runHook(h txHook, committed bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			tx.EventErrKv("dbr.tx.hook.panic", fmt.Errorf("%v", p), kvs{
+				"hook": h.on,
+			})
+		}
+	}()
+	err := h.fn(committed)
+	if err != nil {
+		tx.EventErrKv("dbr.tx.hook.error", err, kvs{
+			"hook": h.on,
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxHook(t *testing.T) {
+	log := &txEventReceiver{}
+	sess, mock := newMockSession(t, dialect.PostgreSQL, log)
+
+	var calls []string
+	hook := func(name string) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	complete := func(committed bool) error {
+		calls = append(calls, "complete")
+		return nil
+	}
+
+	// commit
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	tx.OnCommit(hook("commit 1"))
+	tx.OnRollback(hook("rollback"))
+	tx.OnComplete(complete)
+	tx.OnCommit(hook("commit 2"))
+	require.NoError(t, tx.Commit())
+	tx.RollbackUnlessCommitted()
+	require.Equal(t, []string{"commit 1", "complete", "commit 2"}, calls)
+
+	// rollback
+	calls = nil
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	tx, err = sess.Begin()
+	require.NoError(t, err)
+	tx.OnCommit(hook("commit"))
+	tx.OnRollback(hook("rollback"))
+	tx.OnComplete(complete)
+	tx.RollbackUnlessCommitted()
+	require.Equal(t, []string{"rollback", "complete"}, calls)
+
+	// errors and panics
+	calls = nil
+	log.events = nil
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	tx, err = sess.Begin()
+	require.NoError(t, err)
+	tx.OnCommit(func() error {
+		return errors.New("hook")
+	})
+	tx.OnCommit(func() error {
+		panic("hook")
+	})
+	tx.OnCommit(hook("commit"))
+	require.NoError(t, tx.Commit())
+	require.Equal(t, []string{"commit"}, calls)
+	require.Equal(t, []string{"dbr.begin", "dbr.commit", "dbr.tx.hook.error", "dbr.tx.hook.panic"}, log.events)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNestedTxHook(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL, nil)
+
+	var calls []string
+	hook := func(name string) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT dbr_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT dbr_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT dbr_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	tx.OnCommit(hook("outer"))
+
+	child, err := tx.Begin()
+	require.NoError(t, err)
+	child.OnCommit(hook("committed child"))
+	require.NoError(t, child.Commit())
+	require.Empty(t, calls)
+
+	child, err = tx.Begin()
+	require.NoError(t, err)
+	child.OnCommit(hook("rolled back child"))
+	child.OnRollback(hook("rollback child"))
+	require.NoError(t, child.Rollback())
+	require.Equal(t, []string{"rollback child"}, calls)
+
+	require.NoError(t, tx.Commit())
+	require.Equal(t, []string{"rollback child", "outer", "committed child"}, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
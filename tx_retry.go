@@ -0,0 +1,108 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/gocraft/dbr/v2/dialect"
+)
+
+// TxRetry configures how RunInTx retries a transaction.
+// The zero value retries deadlocks and serialization failures
+// up to 3 times in total with DefaultBackoff.
+type TxRetry struct {
+	// MaxAttempts is the number of times the transaction runs at most.
+	// Zero means 3, and 1 disables retries.
+	MaxAttempts int
+	// Backoff returns the delay before the n-th retry, starting at 1.
+	Backoff func(n int) time.Duration
+	// Retryable reports whether the transaction is retried after err.
+	Retryable func(d Dialect, err error) bool
+}
+
+// DefaultBackoff waits up to 10ms before the first retry,
+// and doubles it for every retry until 1s.
+var DefaultBackoff = ExponentialBackoff(10*time.Millisecond, time.Second)
+
+// ExponentialBackoff returns a random delay up to base * 2^(n-1), capped by max.
+func ExponentialBackoff(base, max time.Duration) func(n int) time.Duration {
+	return func(n int) time.Duration {
+		d := max
+		if n < 32 && base<<(n-1) < max {
+			d = base << (n - 1)
+		}
+		return time.Duration(rand.Int63n(int64(d) + 1))
+	}
+}
+
+// RunInTx runs fn in a transaction, and commits it if fn returns nil.
+//
+// The transaction is rolled back if fn returns an error or panics,
+// and the panic is re-raised after rolling back. If the error is retryable,
+// like a deadlock, the transaction runs again according to TxRetry of the session.
+// fn can run many times, so it should not have side effects outside of tx.
+func (sess *Session) RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	retry := sess.TxRetry
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 3
+	}
+	if retry.Backoff == nil {
+		retry.Backoff = DefaultBackoff
+	}
+	if retry.Retryable == nil {
+		retry.Retryable = isRetryable
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := sess.runInTx(ctx, opts, fn)
+		if err == nil || attempt >= retry.MaxAttempts || !retry.Retryable(sess.Dialect, err) {
+			return err
+		}
+		sess.EventErrKv("dbr.tx.retry", err, kvs{
+			"attempt": strconv.Itoa(attempt),
+		})
+
+		timer := time.NewTimer(retry.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (sess *Session) runInTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	tx, err := sess.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.RollbackUnlessCommitted()
+			panic(p)
+		}
+	}()
+
+	err = fn(tx)
+	if err != nil {
+		tx.RollbackUnlessCommitted()
+		return err
+	}
+	return tx.Commit()
+}
+
+// isRetryable reports whether err is a deadlock or a serialization failure.
+func isRetryable(d Dialect, err error) bool {
+	err = classifyError(d, err)
+	if d == dialect.SQLite3 && errors.Is(err, ErrLockTimeout) {
+		// SQLITE_BUSY is returned on deadlocks as well.
+		return true
+	}
+	return errors.Is(err, ErrDeadlock) || errors.Is(err, ErrSerializationFailure)
+}
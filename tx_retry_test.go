@@ -0,0 +1,138 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// txEventReceiver records events without kvs as well,
+// like dbr.begin and dbr.commit of transactions.
+type txEventReceiver struct {
+	testEventReceiver
+}
+
+func (r *txEventReceiver) Event(eventName string) {
+	r.events = append(r.events, eventName)
+}
+
+func (r *txEventReceiver) EventErr(eventName string, err error) error {
+	r.events = append(r.events, eventName)
+	return err
+}
+
+func TestRunInTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	log := &txEventReceiver{}
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       dialect.MySQL,
+	}
+	sess := conn.NewSession(log)
+	sess.TxRetry.Backoff = func(int) time.Duration { return 0 }
+
+	update := func(tx *Tx) error {
+		_, err := tx.Update("dbr_people").Set("name", "a").Exec()
+		return err
+	}
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+
+	// retried after a deadlock
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `dbr_people`").WillReturnError(deadlock)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `dbr_people`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, sess.RunInTx(context.Background(), nil, update))
+	require.Equal(t, []string{
+		"dbr.begin", "dbr.exec.exec", "dbr.rollback", "dbr.tx.retry",
+		"dbr.begin", "dbr.commit",
+	}, log.events)
+
+	// gives up after MaxAttempts
+	sess.TxRetry.MaxAttempts = 2
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `dbr_people`").WillReturnError(deadlock)
+		mock.ExpectRollback()
+	}
+	err = sess.RunInTx(context.Background(), nil, update)
+	require.ErrorIs(t, err, deadlock)
+	require.ErrorIs(t, err, ErrDeadlock)
+
+	// other errors are not retried
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = sess.RunInTx(context.Background(), nil, func(tx *Tx) error {
+		return ErrNotFound
+	})
+	require.Equal(t, ErrNotFound, err)
+
+	// rolled back on panic
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	require.PanicsWithValue(t, "boom", func() {
+		sess.RunInTx(context.Background(), nil, func(tx *Tx) error {
+			panic("boom")
+		})
+	})
+
+	// stops waiting for a retry when ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	sess.TxRetry.Backoff = func(int) time.Duration {
+		cancel()
+		return time.Hour
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `dbr_people`").WillReturnError(deadlock)
+	mock.ExpectRollback()
+	err = sess.RunInTx(ctx, nil, update)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, ErrDeadlock)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, test := range []struct {
+		d    Dialect
+		err  error
+		want bool
+	}{
+		{d: dialect.MySQL, err: &mysql.MySQLError{Number: 1213}, want: true},
+		{d: dialect.MySQL, err: &mysql.MySQLError{Number: 1062}, want: false},
+		{d: dialect.PostgreSQL, err: &pq.Error{Code: "40001"}, want: true},
+		{d: dialect.PostgreSQL, err: fmt.Errorf("update: %w", &pq.Error{Code: "40P01"}), want: true},
+		{d: dialect.PostgreSQL, err: &pq.Error{Code: "23505"}, want: false},
+		{d: dialect.SQLite3, err: sqlite3.Error{Code: sqlite3.ErrBusy}, want: true},
+		{d: dialect.MSSQL, err: mssql.Error{Number: 1205}, want: true},
+		{d: dialect.MSSQL, err: errors.New("1205"), want: false},
+	} {
+		require.Equal(t, test.want, isRetryable(test.d, test.err), "%v", test.err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	for n, max := range []time.Duration{10, 20, 40, 50, 50} {
+		for i := 0; i < 10; i++ {
+			d := backoff(n + 1)
+			require.True(t, d >= 0 && d <= max*time.Millisecond)
+		}
+	}
+	require.True(t, backoff(100) <= 50*time.Millisecond)
+}
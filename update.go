@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"strconv"
+
+	"github.com/gocraft/dbr/v2/dialect"
 )
 
 // UpdateStmt builds `UPDATE ...`.
@@ -18,13 +20,20 @@ type UpdateStmt struct {
 	Value        map[string]interface{}
 	WhereCond    []Builder
 	ReturnColumn []string
+	ReturnDest   []interface{}
 	LimitCount   int64
 	comments     Comments
 	indexHints   []Builder
+	shard        interface{}
 }
 
 type UpdateBuilder = UpdateStmt
 
+// ToSQL returns the query with placeholders of dialect d, and its arguments.
+func (b *UpdateStmt) ToSQL(d Dialect) (string, []interface{}, error) {
+	return ToSQL(b, d)
+}
+
 func (b *UpdateStmt) Build(d Dialect, buf Buffer) error {
 	if b.raw.Query != "" {
 		return b.raw.Build(d, buf)
@@ -43,15 +52,25 @@ func (b *UpdateStmt) Build(d Dialect, buf Buffer) error {
 		return err
 	}
 
-	buf.WriteString("UPDATE ")
-	buf.WriteString(d.QuoteIdent(b.Table))
-	for _, hint := range b.indexHints {
-		buf.WriteString(" ")
-		if err := hint.Build(d, buf); err != nil {
-			return err
+	if mutationStyle(d) == dialect.AlterTable {
+		// rows are updated with a mutation, like in clickhouse.
+		if len(b.ReturnColumn) > 0 || b.LimitCount >= 0 || len(b.indexHints) > 0 {
+			return ErrNotSupported
 		}
+		buf.WriteString("ALTER TABLE ")
+		buf.WriteString(d.QuoteIdent(b.Table))
+		buf.WriteString(" UPDATE ")
+	} else {
+		buf.WriteString("UPDATE ")
+		buf.WriteString(d.QuoteIdent(b.Table))
+		for _, hint := range b.indexHints {
+			buf.WriteString(" ")
+			if err := hint.Build(d, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(" SET ")
 	}
-	buf.WriteString(" SET ")
 
 	i := 0
 	for col, v := range b.Value {
@@ -67,21 +86,26 @@ func (b *UpdateStmt) Build(d Dialect, buf Buffer) error {
 		i++
 	}
 
+	if returningStyle(d) == dialect.Output {
+		buildOutput(d, buf, b.ReturnColumn)
+	}
+
 	if len(b.WhereCond) > 0 {
 		buf.WriteString(" WHERE ")
 		err := And(b.WhereCond...).Build(d, buf)
 		if err != nil {
 			return err
 		}
+	} else if mutationStyle(d) == dialect.AlterTable {
+		// WHERE is required in a mutation.
+		buf.WriteString(" WHERE ")
+		buf.WriteString(encodeBoolCondition(d, true))
 	}
 
-	if len(b.ReturnColumn) > 0 {
-		buf.WriteString(" RETURNING ")
-		for i, col := range b.ReturnColumn {
-			if i > 0 {
-				buf.WriteString(",")
-			}
-			buf.WriteString(d.QuoteIdent(col))
+	if returningStyle(d) != dialect.Output {
+		err = buildReturning(d, buf, b.ReturnColumn, b.ReturnDest)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -162,12 +186,20 @@ func (b *UpdateStmt) Where(query interface{}, value ...interface{}) *UpdateStmt
 	return b
 }
 
-// Returning specifies the returning columns for postgres.
+// Returning specifies the returning columns for postgres/mssql.
 func (b *UpdateStmt) Returning(column ...string) *UpdateStmt {
 	b.ReturnColumn = column
 	return b
 }
 
+// ReturningInto adds a returning column that is loaded into dest
+// with `RETURNING ... INTO` for oracle.
+func (b *UpdateStmt) ReturningInto(column string, dest interface{}) *UpdateStmt {
+	b.ReturnColumn = append(b.ReturnColumn, column)
+	b.ReturnDest = append(b.ReturnDest, dest)
+	return b
+}
+
 // Set updates column with value.
 func (b *UpdateStmt) Set(column string, value interface{}) *UpdateStmt {
 	b.Value[column] = value
@@ -204,6 +236,13 @@ func (b *UpdateStmt) Comment(comment string) *UpdateStmt {
 	return b
 }
 
+// Shard sets the shard key of the statement, which is used instead of
+// the key in the context when it runs on a ShardedSession.
+func (b *UpdateStmt) Shard(key interface{}) *UpdateStmt {
+	b.shard = key
+	return b
+}
+
 func (b *UpdateStmt) Exec() (sql.Result, error) {
 	return b.ExecContext(context.Background())
 }
@@ -49,6 +49,12 @@ var (
 
 type tagStore struct {
 	m map[reflect.Type][]string
+
+	// for NilStructLoader
+	nilStruct bool
+	depth     int
+	dest      []*nullDest
+	group     []*nullGroup
 }
 
 func newTagStore() *tagStore {
@@ -100,11 +106,20 @@ func (s *tagStore) findPtr(value reflect.Value, name []string, ptr []interface{}
 		}
 		return s.findPtr(value.Elem(), name, ptr)
 	default:
-		ptr[0] = value.Addr().Interface()
+		ptr[0] = scanDest(value)
 		return nil
 	}
 }
 
+// scanDest returns a pointer to value that can be passed to sql.Rows.Scan.
+// Slices other than []byte are scanned as arrays.
+func scanDest(value reflect.Value) interface{} {
+	if isArrayType(value.Type()) {
+		return Array(value.Addr().Interface())
+	}
+	return value.Addr().Interface()
+}
+
 func (s *tagStore) findValueByName(value reflect.Value, name []string, ret []interface{}, retPtr bool) {
 	if value.Type().Implements(typeValuer) {
 		return
@@ -129,13 +144,41 @@ func (s *tagStore) findValueByName(value reflect.Value, name []string, ret []int
 				}
 				if ret[i] == nil {
 					if retPtr {
-						ret[i] = fieldValue.Addr().Interface()
+						ret[i] = s.nullable(scanDest(fieldValue))
 					} else {
 						ret[i] = fieldValue
 					}
 				}
 			}
+			var g *nullGroup
+			if retPtr && s.isNilable(fieldValue) {
+				g = s.beginNull(fieldValue)
+			}
 			s.findValueByName(fieldValue, name, ret, retPtr)
+			switch fieldValue.Kind() {
+			case reflect.Struct, reflect.Ptr:
+				// columns like tag.name are only for this field.
+				if sub := trimPrefix(name, tag); sub != nil {
+					s.findValueByName(fieldValue, sub, ret, retPtr)
+				}
+			}
+			s.endNull(g)
+		}
+	}
+}
+
+// trimPrefix removes prefix and a dot from the front of each name,
+// and replaces names without the prefix with "".
+// It returns nil if no name has the prefix.
+func trimPrefix(name []string, prefix string) []string {
+	var sub []string
+	for i, n := range name {
+		if len(n) > len(prefix) && n[len(prefix)] == '.' && strings.HasPrefix(n, prefix) {
+			if sub == nil {
+				sub = make([]string, len(name))
+			}
+			sub[i] = n[len(prefix)+1:]
 		}
 	}
+	return sub
 }
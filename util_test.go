@@ -97,6 +97,17 @@ func TestFindValueByName(t *testing.T) {
 			name: []string{"test2"},
 			want: []string{"test2"},
 		},
+		{
+			in: struct {
+				User struct {
+					Address struct {
+						City string
+					} `db:"addr"`
+				}
+			}{},
+			name: []string{"user.addr.city", "user.city", "city"},
+			want: []string{"user.addr.city", "user.city", "city"},
+		},
 	} {
 		found := make([]interface{}, len(test.name))
 		s := newTagStore()